@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"go/token"
 	"go/types"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
@@ -97,10 +98,22 @@ func newGenerateOptions(headerFile string) (*wire.GenerateOptions, error) {
 	return opts, nil
 }
 
+// setInjectorFlags defines the flags that set the default injector options
+// on f.
+func setInjectorFlags(f *flag.FlagSet, opts *wire.InjectorOptions) {
+	f.BoolVar(&opts.RecoverPanics, "recover_panics", false, "run the cleanup functions built so far if a provider panics, then panic again")
+	f.BoolVar(&opts.PanicsAsErrors, "panics_as_errors", false, "run the cleanup functions built so far if a provider panics, then return the panic as an error from injectors that can fail")
+	f.BoolVar(&opts.Parallel, "parallel", false, "call independent provider functions concurrently")
+	f.BoolVar(&opts.CheckContext, "check_context", false, "check whether the context is done between provider calls in injectors that take a context and can fail")
+	f.BoolVar(&opts.WrapErrors, "wrap_errors", false, "wrap errors returned by providers in a *wireruntime.ProviderError that names the provider")
+}
+
 type genCmd struct {
 	headerFile     string
 	prefixFileName string
 	tags           string
+	variants       string
+	injectorOpts   wire.InjectorOptions
 }
 
 func (*genCmd) Name() string { return "gen" }
@@ -112,6 +125,9 @@ func (*genCmd) Usage() string {
 
   Given one or more packages, gen creates the wire_gen.go file for each.
 
+  With -variants, gen instead creates a <name>_wire_gen.go file for each
+  variant, built only with the variant's tags.
+
   If no packages are listed, it defaults to ".".
 `
 }
@@ -119,6 +135,8 @@ func (cmd *genCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&cmd.headerFile, "header_file", "", "path to file to insert as a header in wire_gen.go")
 	f.StringVar(&cmd.prefixFileName, "output_file_prefix", "", "string to prepend to output file names.")
 	f.StringVar(&cmd.tags, "tags", "", "append build tags to the default wirebuild")
+	f.StringVar(&cmd.variants, "variants", "", "comma-separated list of name:tags variants to generate a <name>_wire_gen.go file for")
+	setInjectorFlags(f, &cmd.injectorOpts)
 }
 
 func (cmd *genCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
@@ -135,6 +153,12 @@ func (cmd *genCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interfa
 
 	opts.PrefixOutputFile = cmd.prefixFileName
 	opts.Tags = cmd.tags
+	opts.InjectorOptions = cmd.injectorOpts
+	opts.Variants, err = wire.ParseVariants(cmd.variants)
+	if err != nil {
+		log.Println(err)
+		return subcommands.ExitFailure
+	}
 
 	outs, errs := wire.Generate(ctx, wd, os.Environ(), packages(f), opts)
 	if len(errs) > 0 {
@@ -171,8 +195,10 @@ func (cmd *genCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interfa
 }
 
 type diffCmd struct {
-	headerFile string
-	tags       string
+	headerFile   string
+	tags         string
+	variants     string
+	injectorOpts wire.InjectorOptions
 }
 
 func (*diffCmd) Name() string { return "diff" }
@@ -194,6 +220,8 @@ func (*diffCmd) Usage() string {
 func (cmd *diffCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&cmd.headerFile, "header_file", "", "path to file to insert as a header in wire_gen.go")
 	f.StringVar(&cmd.tags, "tags", "", "append build tags to the default wirebuild")
+	f.StringVar(&cmd.variants, "variants", "", "comma-separated list of name:tags variants to generate a <name>_wire_gen.go file for")
+	setInjectorFlags(f, &cmd.injectorOpts)
 }
 func (cmd *diffCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
 	const (
@@ -212,6 +240,12 @@ func (cmd *diffCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interf
 	}
 
 	opts.Tags = cmd.tags
+	opts.InjectorOptions = cmd.injectorOpts
+	opts.Variants, err = wire.ParseVariants(cmd.variants)
+	if err != nil {
+		log.Println(err)
+		return errReturn
+	}
 
 	outs, errs := wire.Generate(ctx, wd, os.Environ(), packages(f), opts)
 	if len(errs) > 0 {
@@ -290,66 +324,74 @@ func (cmd *showCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interf
 	}
 	info, errs := wire.Load(ctx, wd, os.Environ(), cmd.tags, packages(f))
 	if info != nil {
-		keys := make([]wire.ProviderSetID, 0, len(info.Sets))
-		for k := range info.Sets {
-			keys = append(keys, k)
+		showInfo(os.Stdout, info)
+	}
+	if len(errs) > 0 {
+		logErrors(errs)
+		log.Println("error loading packages")
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+// showInfo writes the description of the provider sets and injectors in info
+// printed by the show command to w.
+func showInfo(w io.Writer, info *wire.Info) {
+	keys := make([]wire.ProviderSetID, 0, len(info.Sets))
+	for k := range info.Sets {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].ImportPath == keys[j].ImportPath {
+			return keys[i].VarName < keys[j].VarName
 		}
-		sort.Slice(keys, func(i, j int) bool {
-			if keys[i].ImportPath == keys[j].ImportPath {
-				return keys[i].VarName < keys[j].VarName
-			}
-			return keys[i].ImportPath < keys[j].ImportPath
-		})
-		for i, k := range keys {
-			if i > 0 {
-				fmt.Println()
-			}
-			outGroups, imports := gather(info, k)
-			fmt.Println(k)
-			for _, imp := range sortSet(imports) {
-				fmt.Printf("\t%s\n", imp)
-			}
-			for i := range outGroups {
-				fmt.Printf("\tOutputs given %s:\n", outGroups[i].name)
-				out := make(map[string]token.Pos, outGroups[i].outputs.Len())
-				outGroups[i].outputs.Iterate(func(t types.Type, v interface{}) {
-					switch v := v.(type) {
-					case *wire.Provider:
-						out[types.TypeString(t, nil)] = v.Pos
-					case *wire.Value:
-						out[types.TypeString(t, nil)] = v.Pos
-					case *wire.Field:
-						out[types.TypeString(t, nil)] = v.Pos
-					default:
-						panic("unreachable")
-					}
-				})
-				for _, t := range sortSet(out) {
-					fmt.Printf("\t\t%s\n", t)
-					fmt.Printf("\t\t\tat %v\n", info.Fset.Position(out[t]))
-				}
-			}
+		return keys[i].ImportPath < keys[j].ImportPath
+	})
+	for i, k := range keys {
+		if i > 0 {
+			fmt.Fprintln(w)
 		}
-		if len(info.Injectors) > 0 {
-			injectors := append([]*wire.Injector(nil), info.Injectors...)
-			sort.Slice(injectors, func(i, j int) bool {
-				if injectors[i].ImportPath == injectors[j].ImportPath {
-					return injectors[i].FuncName < injectors[j].FuncName
+		outGroups, imports := gather(info, k)
+		fmt.Fprintln(w, k)
+		for _, imp := range sortSet(imports) {
+			fmt.Fprintf(w, "\t%s\n", imp)
+		}
+		for i := range outGroups {
+			fmt.Fprintf(w, "\tOutputs given %s:\n", outGroups[i].name)
+			out := make(map[string]token.Pos, outGroups[i].outputs.Len())
+			outGroups[i].outputs.Iterate(func(t types.Type, v interface{}) {
+				switch v := v.(type) {
+				case *wire.Provider:
+					out[wire.TypeString(t)] = v.Pos
+				case *wire.Value:
+					out[wire.TypeString(t)] = v.Pos
+				case *wire.Field:
+					out[wire.TypeString(t)] = v.Pos
+				case *wire.Multibinding:
+					out[wire.TypeString(t)] = v.Contributions[0].Pos
+				default:
+					panic("unreachable")
 				}
-				return injectors[i].ImportPath < injectors[j].ImportPath
 			})
-			fmt.Println("\nInjectors:")
-			for _, in := range injectors {
-				fmt.Printf("\t%v\n", in)
+			for _, t := range sortSet(out) {
+				fmt.Fprintf(w, "\t\t%s\n", t)
+				fmt.Fprintf(w, "\t\t\tat %v\n", info.Fset.Position(out[t]))
 			}
 		}
 	}
-	if len(errs) > 0 {
-		logErrors(errs)
-		log.Println("error loading packages")
-		return subcommands.ExitFailure
+	if len(info.Injectors) > 0 {
+		injectors := append([]*wire.Injector(nil), info.Injectors...)
+		sort.Slice(injectors, func(i, j int) bool {
+			if injectors[i].ImportPath == injectors[j].ImportPath {
+				return injectors[i].FuncName < injectors[j].FuncName
+			}
+			return injectors[i].ImportPath < injectors[j].ImportPath
+		})
+		fmt.Fprintln(w, "\nInjectors:")
+		for _, in := range injectors {
+			fmt.Fprintf(w, "\t%v\n", in)
+		}
 	}
-	return subcommands.ExitSuccess
 }
 
 type checkCmd struct {
@@ -390,7 +432,7 @@ func (cmd *checkCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...inter
 type outGroup struct {
 	name    string
 	inputs  *typeutil.Map // values are not important
-	outputs *typeutil.Map // values are *wire.Provider, *wire.Value, or *wire.Field
+	outputs *typeutil.Map // values are *wire.Provider, *wire.Value, *wire.Field or *wire.Multibinding
 }
 
 // gather flattens a provider set into outputs grouped by the inputs
@@ -488,6 +530,59 @@ func gather(info *wire.Info, key wire.ProviderSetID) (_ []outGroup, imports map[
 					inputs:  in,
 					outputs: out,
 				})
+			case pv.IsMultibinding():
+				// Like a provider, but the inputs are those of all the
+				// element providers.
+				m := pv.Multibinding()
+				var args []types.Type
+				for _, c := range m.Contributions {
+					if c.Provider == nil {
+						continue
+					}
+					for _, arg := range c.Provider.Args {
+						args = append(args, arg.Type)
+					}
+				}
+				allPresent := true
+				for _, arg := range args {
+					if inputVisited.At(arg) == nil {
+						allPresent = false
+					}
+				}
+				if !allPresent {
+					stk = append(stk, curr)
+					for _, arg := range args {
+						if inputVisited.At(arg) == nil {
+							stk = append(stk, arg)
+						}
+					}
+					continue dfs
+				}
+				in := new(typeutil.Map)
+				in.SetHasher(hash)
+				for _, arg := range args {
+					i := inputVisited.At(arg).(int)
+					if i == -1 {
+						in.Set(arg, true)
+					} else {
+						mergeTypeSets(in, groups[i].inputs)
+					}
+				}
+				for i := range groups {
+					if sameTypeKeys(groups[i].inputs, in) {
+						groups[i].outputs.Set(curr, m)
+						inputVisited.Set(curr, i)
+						continue dfs
+					}
+				}
+				out := new(typeutil.Map)
+				out.SetHasher(hash)
+				out.Set(curr, m)
+				inputVisited.Set(curr, len(groups))
+				groups = append(groups, outGroup{
+					inputs:  in,
+					outputs: out,
+				})
 			case pv.IsValue():
 				v := pv.Value()
 				for i := range groups {
@@ -553,7 +648,7 @@ func gather(info *wire.Info, key wire.ProviderSetID) (_ []outGroup, imports map[
 		}
 		instr := make([]string, 0, groups[i].inputs.Len())
 		groups[i].inputs.Iterate(func(k types.Type, _ interface{}) {
-			instr = append(instr, types.TypeString(k, nil))
+			instr = append(instr, wire.TypeString(k))
 		})
 		sort.Strings(instr)
 		groups[i].name = strings.Join(instr, ", ")
@@ -0,0 +1,177 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/wire/internal/wire"
+)
+
+func TestShow(t *testing.T) {
+	dir := writeModule(t, `package foo
+
+import "github.com/google/wire"
+
+type Realm string
+
+type Middleware interface {
+	Name() string
+}
+
+type authMW struct {
+	realm Realm
+}
+
+func (m *authMW) Name() string { return "auth:" + string(m.realm) }
+
+func NewAuthMW(realm Realm) *authMW {
+	return &authMW{realm: realm}
+}
+
+type logMW struct{}
+
+func (logMW) Name() string { return "log" }
+
+type Plugin struct{}
+
+func NewCachePlugin() *Plugin {
+	return &Plugin{}
+}
+
+var Set = wire.NewSet(
+	wire.Into(new([]Middleware), NewAuthMW),
+	wire.Into(new([]Middleware), wire.InterfaceValue(new(Middleware), logMW{})),
+	wire.IntoMap(new(map[string]*Plugin), "cache", NewCachePlugin),
+)
+`)
+	info, errs := wire.Load(context.Background(), dir, os.Environ(), "", []string{"."})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	var buf bytes.Buffer
+	showInfo(&buf, info)
+	got := strings.ReplaceAll(buf.String(), dir, "$DIR")
+	want := `"example.com/foo".Set
+	Outputs given no inputs:
+		map[string]*example.com/foo.Plugin
+			at $DIR/foo.go:34:2
+	Outputs given example.com/foo.Realm:
+		[]example.com/foo.Middleware
+			at $DIR/foo.go:32:2
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("show output (-want +got):\n%s", diff)
+	}
+}
+
+func TestShowNamed(t *testing.T) {
+	dir := writeModule(t, `package foo
+
+import "github.com/google/wire"
+
+type DB struct{}
+
+func NewPrimaryDB() *DB {
+	return &DB{}
+}
+
+type Replica struct {
+	DB *DB
+}
+
+func NewReplica(db *DB) *Replica {
+	return &Replica{DB: db}
+}
+
+var Set = wire.NewSet(
+	wire.Named("primary", NewPrimaryDB),
+	wire.NamedParams(NewReplica, "replica"),
+)
+`)
+	info, errs := wire.Load(context.Background(), dir, os.Environ(), "", []string{"."})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	var buf bytes.Buffer
+	showInfo(&buf, info)
+	got := strings.ReplaceAll(buf.String(), dir, "$DIR")
+	want := `"example.com/foo".Set
+	Outputs given no inputs:
+		*example.com/foo.DB named "primary"
+			at $DIR/foo.go:7:6
+	Outputs given *example.com/foo.DB named "replica":
+		*example.com/foo.Replica
+			at $DIR/foo.go:15:6
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("show output (-want +got):\n%s", diff)
+	}
+}
+
+// writeModule writes a module example.com/foo with the given foo.go file
+// next to a copy of the wire package and returns the module's directory.
+func writeModule(t *testing.T, src string) string {
+	t.Helper()
+	root, err := ioutil.TempDir("", "wire_show_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(root) })
+	root, err = filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"foo/go.mod":  "module example.com/foo\n\ngo 1.19\n\nrequire github.com/google/wire v0.1.0\n\nreplace github.com/google/wire => ../wire\n",
+		"foo/foo.go":  src,
+		"wire/go.mod": "module github.com/google/wire\n\ngo 1.19\n",
+	}
+	for _, pkg := range []string{"", "wireruntime"} {
+		pkgDir := filepath.Join("..", "..", pkg)
+		ents, err := ioutil.ReadDir(pkgDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, ent := range ents {
+			name := ent.Name()
+			if ent.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+				continue
+			}
+			content, err := ioutil.ReadFile(filepath.Join(pkgDir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			files[filepath.Join("wire", pkg, name)] = string(content)
+		}
+	}
+	for name, content := range files {
+		dst := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(dst, []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(root, "foo")
+}
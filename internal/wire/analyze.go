@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"go/types"
 	"sort"
@@ -33,6 +34,25 @@ const (
 	structProvider
 	valueExpr
 	selectorExpr
+	multibindingLit
+	interceptorProxy
+	lazyCall
+	factoryFunc
+	zeroVar
+	selectSwitch
+)
+
+// A lazyKind is a form of dependency whose value is built by a closure.
+type lazyKind int
+
+const (
+	// lazyOnce is wire.Lazy[T], which builds T on the first call.
+	lazyOnce lazyKind = iota
+	// lazyFunc is func() (T, error), which builds a new T on each call.
+	lazyFunc
+	// lazyFuncCleanup is func() (T, func(), error), which builds a new T
+	// on each call and returns its cleanup function.
+	lazyFuncCleanup
 )
 
 // A call represents a step of an injector function.  It may be either a
@@ -62,6 +82,10 @@ type call struct {
 	// "argument" will be the value to access fields from.
 	args []int
 
+	// typeArgs is the list of type arguments to instantiate the provider
+	// function with, or nil if the provider is not generic.
+	typeArgs []types.Type
+
 	// varargs is true if the provider function is variadic.
 	varargs bool
 
@@ -75,8 +99,15 @@ type call struct {
 
 	// The following are only set for kind == funcProviderCall:
 
-	// hasCleanup is true if the provider call returns a cleanup function.
-	hasCleanup bool
+	// postConstruct is the name of the method to call on the value right
+	// after it is provided, or the empty string if there is none. It is
+	// also set for kind == structProvider.
+	postConstruct string
+	// cleanup is the kind of cleanup function the provider call returns.
+	cleanup CleanupKind
+	// autoClose is true if the cleanup function is the Close method of
+	// the provided value.
+	autoClose bool
 	// hasErr is true if the provider call returns an error.
 	hasErr bool
 
@@ -84,29 +115,120 @@ type call struct {
 
 	valueExpr     ast.Expr
 	valueTypeInfo *types.Info
+	// valueType is the declared type of the value's variable, or nil to use
+	// the type of valueExpr.
+	valueType types.Type
 
 	// The following are only set for kind == selectorExpr:
 
 	ptrToField bool
+
+	// The following are only set for kind == multibindingLit, whose args
+	// are the elements of the slice or map:
+
+	// keys are the constant map keys of the elements, or nil for a slice.
+	keys         []ast.Expr
+	keyTypeInfos []*types.Info
+
+	// The following are only set for kind == lazyCall:
+
+	lazy lazyKind
+	// lazyOut is the type that the closure builds.
+	lazyOut types.Type
+	// sub is the list of calls in the closure that build lazyOut. Its
+	// indices start at subBase, which is the index of the lazy call
+	// itself. The smaller indices are the injector's arguments and the
+	// values built before the closure, which it captures; they are also
+	// the args of the lazy call.
+	sub     []call
+	subBase int
+	// subOut is the index of the value of type lazyOut, which may be
+	// captured.
+	subOut int
+
+	// The following are only set for kind == factoryFunc, whose args are
+	// the arguments of the provider function that are not passed to the
+	// factory function:
+
+	factory *Factory
+
+	// The following are only set for kind == selectSwitch, whose args are
+	// the arguments of the key function followed by the values that the
+	// branches need. Its keys are the keys of the branches:
+
+	sel *Select
 }
 
 // solve finds the sequence of calls required to produce an output type
 // with an optional set of provided inputs.
 func solve(fset *token.FileSet, out types.Type, given *types.Tuple, set *ProviderSet) ([]call, []error) {
+	calls, _, used, errs := solveGraph(fset, out, nil, given, set, nil)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if errs := verifyArgsUsed(set, used); len(errs) > 0 {
+		return nil, errs
+	}
+	return calls, nil
+}
+
+// solveGraph is like solve, but doesn't verify that the set's providers
+// are used. Instead, it returns the index of out and the sources that were
+// used. from is the type that needs out, or nil if out is the injector's
+// output. If free isn't nil, the types that set doesn't provide are added
+// to it instead of being reported as errors.
+func solveGraph(fset *token.FileSet, out, from types.Type, given *types.Tuple, set *ProviderSet, free *[]types.Type) ([]call, int, []*providerSetSrc, []error) {
+	calls, outIndex, used, index, errs := solveCalls(fset, out, from, given, set, nil, given.Len(), nil, nil, free)
+	if len(errs) > 0 || !hasLazyCall(calls) {
+		return calls, outIndex, used, errs
+	}
+	// Solve again, now that it is known which values are built outside of
+	// closures, so that the closures capture them instead of building
+	// them again.
+	shared := new(typeutil.Map)
+	index.Iterate(func(t types.Type, v interface{}) {
+		if i, ok := v.(int); ok && i >= given.Len() && calls[i-given.Len()].kind != lazyCall {
+			shared.Set(t, true)
+		}
+	})
+	calls, outIndex, used, _, errs = solveCalls(fset, out, from, given, set, nil, given.Len(), shared, nil, free)
+	return calls, outIndex, used, errs
+}
+
+// errAbort is the index value of a type that was visited, but failed due
+// to an error that was reported.
+var errAbort = errors.New("failed to visit")
+
+// solveCalls does the work of solveGraph. outer is the index of the solve
+// of the enclosing closure's calls, or nil for the injector's calls, and
+// base is the index of the first call. The values in outer are captured
+// by the closure. shared holds the types that are built outside of
+// closures, which are built before a closure that needs them, or is nil
+// if they aren't known yet. lazies are the types that are built by the
+// enclosing closures, which can't be built lazily again. solveCalls also
+// returns the index of each type.
+func solveCalls(fset *token.FileSet, out, from types.Type, given *types.Tuple, set *ProviderSet, outer *typeutil.Map, base int, shared *typeutil.Map, lazies []types.Type, free *[]types.Type) ([]call, int, []*providerSetSrc, *typeutil.Map, []error) {
 	ec := new(errorCollector)
 
 	// Start building the mapping of type to local variable of the given type.
-	// The first len(given) local variables are the given types.
+	// The first len(given) local variables are the given types. In a
+	// closure, the first base local variables are those of the enclosing
+	// function that were built before the closure.
 	index := new(typeutil.Map)
-	for i := 0; i < given.Len(); i++ {
-		index.Set(given.At(i).Type(), i)
+	if outer != nil {
+		outer.Iterate(func(t types.Type, v interface{}) {
+			index.Set(t, v)
+		})
+	} else {
+		for i := 0; i < given.Len(); i++ {
+			index.Set(given.At(i).Type(), i)
+		}
 	}
 
 	// Topological sort of the directed graph defined by the providers
 	// using a depth-first search using a stack. Provider set graphs are
 	// guaranteed to be acyclic. An index value of errAbort indicates that
 	// the type was visited, but failed due to an error added to ec.
-	errAbort := errors.New("failed to visit")
 	var used []*providerSetSrc
 	var calls []call
 	type frame struct {
@@ -114,7 +236,63 @@ func solve(fset *token.FileSet, out types.Type, given *types.Tuple, set *Provide
 		from types.Type
 		up   *frame
 	}
-	stk := []frame{{t: out}}
+
+	// Instantiations of generic providers are made on demand and cached
+	// for the duration of the solve.
+	instances := new(typeutil.Map) // to *genericProvider
+	numInstances := make(map[*Provider]int)
+	lookup := func(t types.Type) (ProvidedType, *providerSetSrc, error) {
+		if pt, _ := set.providerMap.At(t).(*ProvidedType); pt != nil {
+			return *pt, set.srcMap.At(t).(*providerSetSrc), nil
+		}
+		if inst, _ := instances.At(t).(*genericProvider); inst != nil {
+			return ProvidedType{t: t, p: inst.p}, inst.src, nil
+		}
+		gp, inst, err := set.instantiate(fset, t)
+		if err != nil || inst == nil {
+			return ProvidedType{}, nil, err
+		}
+		if numInstances[gp.p]++; numInstances[gp.p] > maxInstances {
+			return ProvidedType{}, nil, fmt.Errorf("generic provider %s.%s instantiated more than %d times; is it recursive?", gp.p.Pkg.Name(), gp.p.Name, maxInstances)
+		}
+		instances.Set(t, &genericProvider{p: inst, src: gp.src})
+		return ProvidedType{t: t, p: inst}, gp.src, nil
+	}
+
+	// decorate adds the calls to the decorators of t, which wrap the value
+	// at index.At(t) in turn.
+	decorate := func(t types.Type, decorators []*Decorator) {
+		for _, d := range decorators {
+			c, ok := providerCall(d.Provider, t, index)
+			if !ok {
+				index.Set(t, errAbort)
+				return
+			}
+			index.Set(t, base+len(calls))
+			calls = append(calls, c)
+			used = append(used, &providerSetSrc{Decorator: d})
+		}
+	}
+
+	// intercept adds the call that wraps the value at index.At(t) in a proxy
+	// that calls the interceptor of ic.
+	intercept := func(t types.Type, ic *Intercept) {
+		interceptor, ok := index.At(ic.Interceptor).(int)
+		if !ok {
+			index.Set(t, errAbort)
+			return
+		}
+		calls = append(calls, call{
+			kind: interceptorProxy,
+			out:  t,
+			args: []int{index.At(t).(int), interceptor},
+			ins:  []types.Type{t, ic.Interceptor},
+		})
+		index.Set(t, base+len(calls)-1)
+		used = append(used, &providerSetSrc{Intercept: ic})
+	}
+
+	stk := []frame{{t: out, from: from}}
 dfs:
 	for len(stk) > 0 {
 		curr := stk[len(stk)-1]
@@ -122,24 +300,156 @@ dfs:
 		if index.At(curr.t) != nil {
 			continue
 		}
+		// Provider sets are verified to be acyclic, but instantiations of
+		// generic providers are not, so check the path to this type.
+		for f := curr.up; f != nil; f = f.up {
+			if !types.Identical(f.t, curr.t) {
+				continue
+			}
+			sb := new(strings.Builder)
+			fmt.Fprintf(sb, "cycle for %s:\n", typeString(curr.t))
+			var path []string
+			for g := curr.up; g != f.up; g = g.up {
+				pv, src, _ := lookup(g.t)
+				switch {
+				case pv.IsProvider():
+					p := pv.Provider()
+					path = append(path, fmt.Sprintf("%s (%s.%s) ->\n", typeString(g.t), p.Pkg.Path(), p.Name))
+				case pv.IsField():
+					p := pv.Field()
+					path = append(path, fmt.Sprintf("%s (%s.%s) ->\n", typeString(g.t), p.Parent, p.Name))
+				default:
+					path = append(path, fmt.Sprintf("%s (%s) ->\n", typeString(g.t), src.description(fset, g.t)))
+				}
+			}
+			for i := len(path) - 1; i >= 0; i-- {
+				sb.WriteString(path[i])
+			}
+			sb.WriteString(typeString(curr.t))
+			ec.add(errors.New(sb.String()))
+			index.Set(curr.t, errAbort)
+			continue dfs
+		}
 
-		pv := set.For(curr.t)
+		pv, src, err := lookup(curr.t)
+		if err != nil {
+			ec.add(err)
+			index.Set(curr.t, errAbort)
+			continue
+		}
 		if pv.IsNil() {
+			if free != nil {
+				found := false
+				for _, t := range *free {
+					if types.Identical(t, curr.t) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					*free = append(*free, curr.t)
+				}
+				index.Set(curr.t, errAbort)
+				continue
+			}
+			if lazyOut, kind, ok := lazyType(curr.t); ok {
+				for _, l := range lazies {
+					if types.Identical(l, lazyOut) {
+						ec.add(fmt.Errorf("cycle for %s: building it needs %s", typeString(lazyOut), typeString(curr.t)))
+						index.Set(curr.t, errAbort)
+						continue dfs
+					}
+				}
+				subLazies := append(lazies[:len(lazies):len(lazies)], lazyOut)
+				subBase := base + len(calls)
+				if shared != nil {
+					// Build the values that the closure needs and that are
+					// built outside of closures anyway before the closure.
+					sub, _, _, _, _ := solveCalls(fset, lazyOut, curr.t, given, set, index, subBase, nil, subLazies, nil)
+					visitedDeps := true
+					builtTypes := closureTypes(sub)
+					for i := len(builtTypes) - 1; i >= 0; i-- {
+						t := builtTypes[i]
+						if shared.At(t) != nil && index.At(t) == nil {
+							if visitedDeps {
+								stk = append(stk, curr)
+								visitedDeps = false
+							}
+							stk = append(stk, frame{t: t, from: curr.t, up: &curr})
+						}
+					}
+					if !visitedDeps {
+						continue
+					}
+				}
+				sub, subOut, subUsed, _, errs := solveCalls(fset, lazyOut, curr.t, given, set, index, subBase, shared, subLazies, nil)
+				if len(errs) > 0 {
+					ec.add(errs...)
+					index.Set(curr.t, errAbort)
+					continue
+				}
+				used = append(used, subUsed...)
+				index.Set(curr.t, subBase)
+				calls = append(calls, call{
+					kind:    lazyCall,
+					out:     curr.t,
+					args:    capturedArgs(sub, subOut, subBase),
+					lazy:    kind,
+					lazyOut: lazyOut,
+					sub:     sub,
+					subOut:  subOut,
+					subBase: subBase,
+				})
+				continue
+			}
+			if o, _ := set.optionals.At(curr.t).(*Optional); o != nil && curr.from != nil {
+				index.Set(curr.t, base+len(calls))
+				calls = append(calls, call{
+					kind: zeroVar,
+					out:  curr.t,
+				})
+				used = append(used, optionalSrc(set, curr.t, o))
+				continue
+			}
 			if curr.from == nil {
-				ec.add(fmt.Errorf("no provider found for %s, output of injector", types.TypeString(curr.t, nil)))
+				ec.add(fmt.Errorf("no provider found for %s, output of injector", typeString(curr.t)))
 				index.Set(curr.t, errAbort)
 				continue
 			}
 			sb := new(strings.Builder)
-			fmt.Fprintf(sb, "no provider found for %s", types.TypeString(curr.t, nil))
+			fmt.Fprintf(sb, "no provider found for %s", typeString(curr.t))
 			for f := curr.up; f != nil; f = f.up {
-				fmt.Fprintf(sb, "\nneeded by %s in %s", types.TypeString(f.t, nil), set.srcMap.At(f.t).(*providerSetSrc).description(fset, f.t))
+				_, src, _ := lookup(f.t)
+				fmt.Fprintf(sb, "\nneeded by %s in %s", typeString(f.t), src.description(fset, f.t))
 			}
 			ec.add(errors.New(sb.String()))
 			index.Set(curr.t, errAbort)
 			continue
 		}
-		src := set.srcMap.At(curr.t).(*providerSetSrc)
+		// Ensure that the other arguments of the decorators have been
+		// visited before the value is provided.
+		decorators, _ := set.decorators.At(curr.t).([]*Decorator)
+		ic, _ := set.intercepts.At(curr.t).(*Intercept)
+		visitedDeps := true
+		if ic != nil && index.At(ic.Interceptor) == nil {
+			stk = append(stk, curr, frame{t: ic.Interceptor, from: curr.t, up: &curr})
+			visitedDeps = false
+		}
+		for i := len(decorators) - 1; i >= 0; i-- {
+			args := decorators[i].Provider.Args
+			for j := len(args) - 1; j >= 1; j-- {
+				if index.At(args[j].Type) == nil {
+					if visitedDeps {
+						stk = append(stk, curr)
+						visitedDeps = false
+					}
+					stk = append(stk, frame{t: args[j].Type, from: curr.t, up: &curr})
+				}
+			}
+		}
+		if !visitedDeps {
+			continue
+		}
 		used = append(used, src)
 		if concrete := pv.Type(); !types.Identical(concrete, curr.t) {
 			// Interface binding does not create a call.
@@ -149,10 +459,16 @@ dfs:
 				continue
 			}
 			index.Set(curr.t, i)
+			if _, ok := i.(int); ok && ic != nil {
+				intercept(curr.t, ic)
+			}
+			if _, ok := index.At(curr.t).(int); ok {
+				decorate(curr.t, decorators)
+			}
 			continue
 		}
 
-		switch pv := set.For(curr.t); {
+		switch {
 		case pv.IsArg():
 			// Continue, already added to stk.
 		case pv.IsProvider():
@@ -175,47 +491,74 @@ dfs:
 			if !visitedArgs {
 				continue
 			}
-			args := make([]int, len(p.Args))
-			ins := make([]types.Type, len(p.Args))
-			for i := range p.Args {
-				ins[i] = p.Args[i].Type
-				v := index.At(p.Args[i].Type)
-				if v == errAbort {
-					index.Set(curr.t, errAbort)
-					continue dfs
+			c, ok := providerCall(p, curr.t, index)
+			if !ok {
+				index.Set(curr.t, errAbort)
+				continue dfs
+			}
+			if pc, _ := set.postConstructs.At(curr.t).(*PostConstruct); pc != nil {
+				c.postConstruct = pc.Method
+				used = append(used, &providerSetSrc{PostConstruct: pc})
+			}
+			index.Set(curr.t, base+len(calls))
+			calls = append(calls, c)
+		case pv.IsValue():
+			index.Set(curr.t, base+len(calls))
+			calls = append(calls, valueCall(pv.Value(), curr.t))
+		case pv.IsMultibinding():
+			m := pv.Multibinding()
+			// Ensure that the arguments of all the element providers have
+			// been visited, as for a provider.
+			visitedArgs := true
+			for i := len(m.Contributions) - 1; i >= 0; i-- {
+				p := m.Contributions[i].Provider
+				if p == nil {
+					continue
 				}
-				args[i] = v.(int)
-			}
-			index.Set(curr.t, given.Len()+len(calls))
-			kind := funcProviderCall
-			fieldNames := []string(nil)
-			if p.IsStruct {
-				kind = structProvider
-				for _, arg := range p.Args {
-					fieldNames = append(fieldNames, arg.FieldName)
+				for j := len(p.Args) - 1; j >= 0; j-- {
+					a := p.Args[j]
+					if index.At(a.Type) == nil {
+						if visitedArgs {
+							stk = append(stk, curr)
+							visitedArgs = false
+						}
+						stk = append(stk, frame{t: a.Type, from: curr.t, up: &curr})
+					}
 				}
 			}
-			calls = append(calls, call{
-				kind:       kind,
-				pkg:        p.Pkg,
-				name:       p.Name,
-				args:       args,
-				varargs:    p.Varargs,
-				fieldNames: fieldNames,
-				ins:        ins,
-				out:        curr.t,
-				hasCleanup: p.HasCleanup,
-				hasErr:     p.HasErr,
-			})
-		case pv.IsValue():
-			v := pv.Value()
-			index.Set(curr.t, given.Len()+len(calls))
-			calls = append(calls, call{
-				kind:          valueExpr,
-				out:           curr.t,
-				valueExpr:     v.expr,
-				valueTypeInfo: v.info,
-			})
+			if !visitedArgs {
+				continue
+			}
+			// The elements are not in index, since several elements may
+			// have the same type. Add a call for each of them, followed by
+			// the call that collects them.
+			collect := call{
+				kind: multibindingLit,
+				out:  curr.t,
+			}
+			for _, contrib := range m.Contributions {
+				var c call
+				if contrib.Provider != nil {
+					var ok bool
+					c, ok = providerCall(contrib.Provider, contrib.Out, index)
+					if !ok {
+						index.Set(curr.t, errAbort)
+						continue dfs
+					}
+				} else {
+					c = valueCall(contrib.Value, contrib.Out)
+				}
+				collect.args = append(collect.args, base+len(calls))
+				collect.ins = append(collect.ins, contrib.Out)
+				if contrib.key != nil {
+					collect.keys = append(collect.keys, contrib.key)
+					collect.keyTypeInfos = append(collect.keyTypeInfos, contrib.keyInfo)
+				}
+				calls = append(calls, c)
+			}
+			used = append(used, m.srcs...)
+			index.Set(curr.t, base+len(calls))
+			calls = append(calls, collect)
 		case pv.IsField():
 			f := pv.Field()
 			if index.At(f.Parent) == nil {
@@ -224,7 +567,7 @@ dfs:
 				stk = append(stk, curr, frame{t: f.Parent, from: curr.t, up: &curr})
 				continue
 			}
-			index.Set(curr.t, given.Len()+len(calls))
+			index.Set(curr.t, base+len(calls))
 			v := index.At(f.Parent)
 			if v == errAbort {
 				index.Set(curr.t, errAbort)
@@ -246,14 +589,157 @@ dfs:
 		default:
 			panic("unknown return value from ProviderSet.For")
 		}
+		if _, ok := index.At(curr.t).(int); ok {
+			decorate(curr.t, decorators)
+		}
 	}
 	if len(ec.errors) > 0 {
-		return nil, ec.errors
+		return nil, 0, nil, nil, ec.errors
 	}
-	if errs := verifyArgsUsed(set, used); len(errs) > 0 {
-		return nil, errs
+	// out isn't built if it needs free types.
+	outIndex, _ := index.At(out).(int)
+	return calls, outIndex, used, index, nil
+}
+
+// hasLazyCall reports whether calls has a lazy call.
+func hasLazyCall(calls []call) bool {
+	for i := range calls {
+		if calls[i].kind == lazyCall {
+			return true
+		}
 	}
-	return calls, nil
+	return false
+}
+
+// closureTypes returns the types of the values built by calls, including
+// those built by the closures of lazy calls, in the order they are built.
+func closureTypes(calls []call) []types.Type {
+	var ts []types.Type
+	for i := range calls {
+		if calls[i].kind == lazyCall {
+			ts = append(ts, closureTypes(calls[i].sub)...)
+			continue
+		}
+		ts = append(ts, calls[i].out)
+	}
+	return ts
+}
+
+// capturedArgs returns the indices below base that the calls of a closure
+// and its value at index out use, in increasing order.
+func capturedArgs(calls []call, out, base int) []int {
+	captured := make(map[int]bool)
+	if out < base {
+		captured[out] = true
+	}
+	for i := range calls {
+		for _, a := range calls[i].args {
+			if a < base {
+				captured[a] = true
+			}
+		}
+	}
+	args := make([]int, 0, len(captured))
+	for a := range captured {
+		args = append(args, a)
+	}
+	sort.Ints(args)
+	return args
+}
+
+// lazyType returns the type built by t and the kind of closure that builds
+// it if t is wire.Lazy[T], func() (T, error) or func() (T, func(), error).
+func lazyType(t types.Type) (types.Type, lazyKind, bool) {
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "github.com/google/wire" && obj.Name() == "Lazy" && named.TypeArgs().Len() == 1 {
+			return named.TypeArgs().At(0), lazyOnce, true
+		}
+		return nil, 0, false
+	}
+	sig, ok := t.(*types.Signature)
+	if !ok || sig.Params().Len() != 0 || sig.Variadic() {
+		return nil, 0, false
+	}
+	results := sig.Results()
+	if results.Len() < 2 || results.Len() > 3 || !types.Identical(results.At(results.Len()-1).Type(), errorType) {
+		return nil, 0, false
+	}
+	if results.Len() == 3 {
+		if !types.Identical(results.At(1).Type(), cleanupType) {
+			return nil, 0, false
+		}
+		return results.At(0).Type(), lazyFuncCleanup, true
+	}
+	return results.At(0).Type(), lazyFunc, true
+}
+
+// providerCall returns the call to the provider p that produces out. index
+// maps the types of p's arguments to their positions in the injector. It
+// returns false if one of the arguments could not be produced.
+func providerCall(p *Provider, out types.Type, index *typeutil.Map) (call, bool) {
+	args := make([]int, len(p.Args))
+	ins := make([]types.Type, len(p.Args))
+	for i := range p.Args {
+		ins[i] = p.Args[i].Type
+		v, ok := index.At(p.Args[i].Type).(int)
+		if !ok {
+			return call{}, false
+		}
+		args[i] = v
+	}
+	kind := funcProviderCall
+	fieldNames := []string(nil)
+	switch {
+	case p.IsStruct:
+		kind = structProvider
+		for _, arg := range p.Args {
+			fieldNames = append(fieldNames, arg.FieldName)
+		}
+	case p.Factory != nil:
+		kind = factoryFunc
+	case p.Select != nil:
+		kind = selectSwitch
+	}
+	c := call{
+		kind:       kind,
+		pkg:        p.Pkg,
+		name:       p.Name,
+		typeArgs:   p.TypeArgs,
+		args:       args,
+		varargs:    p.Varargs,
+		fieldNames: fieldNames,
+		ins:        ins,
+		out:        out,
+		cleanup:    p.Cleanup,
+		autoClose:  p.AutoClose,
+		hasErr:     p.HasErr,
+		factory:    p.Factory,
+	}
+	if p.Select != nil {
+		// An unknown key is an error.
+		c.hasErr = true
+		c.sel = p.Select
+		c.keys = p.Select.keys
+		for range c.keys {
+			c.keyTypeInfos = append(c.keyTypeInfos, p.Select.keyInfo)
+		}
+	}
+	return c, true
+}
+
+// valueCall returns the step that produces out from the value v.
+func valueCall(v *Value, out types.Type) call {
+	c := call{
+		kind:          valueExpr,
+		out:           out,
+		valueExpr:     v.expr,
+		valueTypeInfo: v.info,
+	}
+	if v.typed {
+		c.valueType = v.Out
+	}
+	return c
 }
 
 // verifyArgsUsed ensures that all of the arguments in set were used during solve.
@@ -296,7 +782,7 @@ func verifyArgsUsed(set *ProviderSet, used []*providerSetSrc) []error {
 			}
 		}
 		if !found {
-			errs = append(errs, fmt.Errorf("unused value of type %s", types.TypeString(v.Out, nil)))
+			errs = append(errs, fmt.Errorf("unused value of type %s", typeString(v.Out)))
 		}
 	}
 	for _, b := range set.Bindings {
@@ -323,19 +809,153 @@ func verifyArgsUsed(set *ProviderSet, used []*providerSetSrc) []error {
 			errs = append(errs, fmt.Errorf("unused field %q.%s", f.Parent, f.Name))
 		}
 	}
+	for _, c := range set.Contributions {
+		found := false
+		for _, u := range used {
+			if u.Contribution == c {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("unused contribution to %s", typeString(c.Collection)))
+		}
+	}
+	for _, o := range set.Overrides {
+		found := false
+		for _, u := range used {
+			if u.Override == o {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("unused override of %s", typeString(o.types()[0])))
+		}
+	}
+	for _, d := range set.Defaults {
+		found := false
+		for _, u := range used {
+			if u.Default == d {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("unused default for %s", typeString(d.types()[0])))
+		}
+	}
+	for _, pc := range set.PostConstructs {
+		found := false
+		for _, u := range used {
+			if u.PostConstruct == pc {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("unused post-construct method of %s", typeString(pc.Type)))
+		}
+	}
+	for _, d := range set.Decorators {
+		found := false
+		for _, u := range used {
+			if u.Decorator == d {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("unused decorator %q", d.Provider.Pkg.Name()+"."+d.Provider.Name))
+		}
+	}
+	for _, ic := range set.Intercepts {
+		found := false
+		for _, u := range used {
+			if u.Intercept == ic {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("unused interceptor of %s", typeString(ic.Iface)))
+		}
+	}
+	for _, o := range set.Optionals {
+		found := false
+		for _, u := range used {
+			if u.Optional == o {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("unused optional %s", typeString(o.Type)))
+		}
+	}
 	return errs
 }
 
-// buildProviderMap creates the providerMap and srcMap fields for a given
-// provider set. The given provider set's providerMap and srcMap fields are
-// ignored.
-func buildProviderMap(fset *token.FileSet, hasher typeutil.Hasher, set *ProviderSet) (*typeutil.Map, *typeutil.Map, []error) {
+// optionalSrc returns the source of the optional o for typ in set: o itself
+// if set declares it, or the imported set that it comes from otherwise.
+func optionalSrc(set *ProviderSet, typ types.Type, o *Optional) *providerSetSrc {
+	for _, imp := range set.Imports {
+		if imp.optionals.At(typ) == o {
+			return &providerSetSrc{Import: imp}
+		}
+	}
+	return &providerSetSrc{Optional: o}
+}
+
+// buildProviderMap creates the providerMap, srcMap and defaults fields for a
+// given provider set. The given provider set's providerMap, srcMap and
+// defaults fields are ignored.
+func buildProviderMap(fset *token.FileSet, hasher typeutil.Hasher, set *ProviderSet) (*typeutil.Map, *typeutil.Map, *typeutil.Map, []genericProvider, []error) {
 	providerMap := new(typeutil.Map)
 	providerMap.SetHasher(hasher)
 	srcMap := new(typeutil.Map) // to *providerSetSrc
 	srcMap.SetHasher(hasher)
+	defaults := new(typeutil.Map) // to *providerSetSrc
+	defaults.SetHasher(hasher)
+	// replaced holds the types whose default was replaced.
+	replaced := new(typeutil.Map) // to bool
+	replaced.SetHasher(hasher)
+	// defaultConflicts are the errors for types provided by several
+	// defaults, which are reported if nothing else in the set provides
+	// the types.
+	type defaultConflict struct {
+		typ types.Type
+		err error
+	}
+	var defaultConflicts []defaultConflict
+	var generics []genericProvider
 
 	ec := new(errorCollector)
+	// taken reports whether typ is already provided by something other
+	// than a default, and adds a conflict error with src if it is. A
+	// default provider of typ is replaced.
+	taken := func(typ types.Type, src *providerSetSrc) bool {
+		prevSrc := srcMap.At(typ)
+		if prevSrc == nil {
+			return false
+		}
+		if defaults.At(typ) != nil {
+			defaults.Delete(typ)
+			replaced.Set(typ, true)
+			return false
+		}
+		ec.add(bindingConflictError(fset, typ, set, src, prevSrc.(*providerSetSrc)))
+		return true
+	}
+	addGeneric := func(p *Provider, src *providerSetSrc) {
+		for _, prev := range generics {
+			if prev.p == p {
+				ec.add(bindingConflictError(fset, p.Out[0], set, src, prev.src))
+				return
+			}
+		}
+		generics = append(generics, genericProvider{p: p, src: src})
+	}
 	// Process injector arguments.
 	if set.InjectorArgs != nil {
 		givens := set.InjectorArgs.Tuple
@@ -343,8 +963,7 @@ func buildProviderMap(fset *token.FileSet, hasher typeutil.Hasher, set *Provider
 			typ := givens.At(i).Type()
 			arg := &InjectorArg{Args: set.InjectorArgs, Index: i}
 			src := &providerSetSrc{InjectorArg: arg}
-			if prevSrc := srcMap.At(typ); prevSrc != nil {
-				ec.add(bindingConflictError(fset, typ, set, src, prevSrc.(*providerSetSrc)))
+			if taken(typ, src) {
 				continue
 			}
 			providerMap.Set(typ, &ProvidedType{t: typ, a: arg})
@@ -355,24 +974,47 @@ func buildProviderMap(fset *token.FileSet, hasher typeutil.Hasher, set *Provider
 	for _, imp := range set.Imports {
 		src := &providerSetSrc{Import: imp}
 		imp.providerMap.Iterate(func(k types.Type, v interface{}) {
-			if prevSrc := srcMap.At(k); prevSrc != nil {
-				ec.add(bindingConflictError(fset, k, set, src, prevSrc.(*providerSetSrc)))
+			if m := v.(*ProvidedType).m; m != nil {
+				for _, c := range m.Contributions {
+					if err := addContribution(fset, set, providerMap, srcMap, c, src); err != nil {
+						ec.add(err)
+					}
+				}
+				return
+			}
+			if imp.defaults.At(k) != nil {
+				// An imported default yields to anything else, but
+				// conflicts with other defaults.
+				if prevSrc := srcMap.At(k); prevSrc != nil {
+					if defaults.At(k) != nil {
+						defaultConflicts = append(defaultConflicts, defaultConflict{k, bindingConflictError(fset, k, set, src, prevSrc.(*providerSetSrc))})
+					}
+					return
+				}
+				defaults.Set(k, src)
+			} else if taken(k, src) {
 				return
 			}
 			providerMap.Set(k, v)
 			srcMap.Set(k, src)
 		})
+		for _, gp := range imp.genericProviders {
+			addGeneric(gp.p, src)
+		}
 	}
 	if len(ec.errors) > 0 {
-		return nil, nil, ec.errors
+		return nil, nil, nil, nil, ec.errors
 	}
 
 	// Process non-binding providers in new set.
 	for _, p := range set.Providers {
 		src := &providerSetSrc{Provider: p}
+		if p.TypeParams != nil {
+			addGeneric(p, src)
+			continue
+		}
 		for _, typ := range p.Out {
-			if prevSrc := srcMap.At(typ); prevSrc != nil {
-				ec.add(bindingConflictError(fset, typ, set, src, prevSrc.(*providerSetSrc)))
+			if taken(typ, src) {
 				continue
 			}
 			providerMap.Set(typ, &ProvidedType{t: typ, p: p})
@@ -381,8 +1023,7 @@ func buildProviderMap(fset *token.FileSet, hasher typeutil.Hasher, set *Provider
 	}
 	for _, v := range set.Values {
 		src := &providerSetSrc{Value: v}
-		if prevSrc := srcMap.At(v.Out); prevSrc != nil {
-			ec.add(bindingConflictError(fset, v.Out, set, src, prevSrc.(*providerSetSrc)))
+		if taken(v.Out, src) {
 			continue
 		}
 		providerMap.Set(v.Out, &ProvidedType{t: v.Out, v: v})
@@ -391,42 +1032,333 @@ func buildProviderMap(fset *token.FileSet, hasher typeutil.Hasher, set *Provider
 	for _, f := range set.Fields {
 		src := &providerSetSrc{Field: f}
 		for _, typ := range f.Out {
-			if prevSrc := srcMap.At(typ); prevSrc != nil {
-				ec.add(bindingConflictError(fset, typ, set, src, prevSrc.(*providerSetSrc)))
+			if taken(typ, src) {
 				continue
 			}
 			providerMap.Set(typ, &ProvidedType{t: typ, f: f})
 			srcMap.Set(typ, src)
 		}
 	}
+	for _, c := range set.Contributions {
+		if err := addContribution(fset, set, providerMap, srcMap, c, &providerSetSrc{Contribution: c}); err != nil {
+			ec.add(err)
+		}
+	}
 	if len(ec.errors) > 0 {
-		return nil, nil, ec.errors
+		return nil, nil, nil, nil, ec.errors
 	}
 
 	// Process bindings in set. Must happen after the other providers to
 	// ensure the concrete type is being provided.
 	for _, b := range set.Bindings {
 		src := &providerSetSrc{Binding: b}
-		if prevSrc := srcMap.At(b.Iface); prevSrc != nil {
-			ec.add(bindingConflictError(fset, b.Iface, set, src, prevSrc.(*providerSetSrc)))
+		if taken(b.Iface, src) {
 			continue
 		}
 		concrete := providerMap.At(b.Provided)
 		if concrete == nil {
-			setName := set.VarName
-			if setName == "" {
-				setName = "provider set"
+			// The concrete type may come from a generic provider.
+			gp, p, err := (&ProviderSet{genericProviders: generics}).instantiate(fset, b.Provided)
+			if err != nil {
+				ec.add(notePosition(fset.Position(b.Pos), err))
+				continue
+			}
+			if p != nil {
+				concrete = &ProvidedType{t: b.Provided, p: p}
+				providerMap.Set(b.Provided, concrete)
+				srcMap.Set(b.Provided, gp.src)
 			}
-			ec.add(notePosition(fset.Position(b.Pos), fmt.Errorf("wire.Bind of concrete type %q to interface %q, but %s does not include a provider for %q", b.Provided, b.Iface, setName, b.Provided)))
+		}
+		if concrete == nil {
+			ec.add(notePosition(fset.Position(b.Pos), fmt.Errorf("wire.Bind of concrete type %q to interface %q, but %s does not include a provider for %q", b.Provided, b.Iface, setName(set), b.Provided)))
 			continue
 		}
 		providerMap.Set(b.Iface, concrete)
 		srcMap.Set(b.Iface, src)
 	}
 	if len(ec.errors) > 0 {
-		return nil, nil, ec.errors
+		return nil, nil, nil, nil, ec.errors
+	}
+
+	// Process defaults. Must happen after the other providers and bindings
+	// so that the defaults yield to them.
+	for _, d := range set.Defaults {
+		src := &providerSetSrc{Default: d}
+		var pts []*ProvidedType
+		switch {
+		case d.Provider != nil:
+			for _, typ := range d.Provider.Out {
+				pts = append(pts, &ProvidedType{t: typ, p: d.Provider})
+			}
+		case d.Value != nil:
+			pts = append(pts, &ProvidedType{t: d.Value.Out, v: d.Value})
+		case d.Binding != nil:
+			concrete := providerMap.At(d.Binding.Provided)
+			if concrete == nil {
+				ec.add(notePosition(fset.Position(d.Pos), fmt.Errorf("wire.Default binding of interface %s to concrete type %s, but %s does not include a provider for %s", typeString(d.Binding.Iface), typeString(d.Binding.Provided), setName(set), typeString(d.Binding.Provided))))
+				continue
+			}
+			pts = append(pts, concrete.(*ProvidedType))
+		}
+		for i, pt := range pts {
+			typ := d.types()[i]
+			if prevSrc := srcMap.At(typ); prevSrc != nil {
+				if defaults.At(typ) != nil {
+					defaultConflicts = append(defaultConflicts, defaultConflict{typ, bindingConflictError(fset, typ, set, src, prevSrc.(*providerSetSrc))})
+				}
+				continue
+			}
+			providerMap.Set(typ, pt)
+			srcMap.Set(typ, src)
+			defaults.Set(typ, src)
+		}
+	}
+	if len(ec.errors) > 0 {
+		return nil, nil, nil, nil, ec.errors
+	}
+
+	// Process overrides. Must happen last so that they replace providers
+	// from the rest of the set, including its imports.
+	overridden := new(typeutil.Map) // to *providerSetSrc
+	overridden.SetHasher(hasher)
+	for _, o := range set.Overrides {
+		src := &providerSetSrc{Override: o}
+		var pts []*ProvidedType
+		switch {
+		case o.Provider != nil:
+			for _, typ := range o.Provider.Out {
+				pts = append(pts, &ProvidedType{t: typ, p: o.Provider})
+			}
+		case o.Value != nil:
+			pts = append(pts, &ProvidedType{t: o.Value.Out, v: o.Value})
+		case o.Binding != nil:
+			concrete := providerMap.At(o.Binding.Provided)
+			if concrete == nil {
+				ec.add(notePosition(fset.Position(o.Pos), fmt.Errorf("wire.Override of interface %s with concrete type %s, but %s does not include a provider for %s", typeString(o.Binding.Iface), typeString(o.Binding.Provided), setName(set), typeString(o.Binding.Provided))))
+				continue
+			}
+			pts = append(pts, concrete.(*ProvidedType))
+		}
+		matched := false
+		for i, pt := range pts {
+			typ := o.types()[i]
+			if prevSrc := overridden.At(typ); prevSrc != nil {
+				ec.add(bindingConflictError(fset, typ, set, src, prevSrc.(*providerSetSrc)))
+				matched = true
+				continue
+			}
+			if providerMap.At(typ) != nil {
+				matched = true
+			} else if _, p, _ := (&ProviderSet{genericProviders: generics}).instantiate(fset, typ); p != nil {
+				matched = true
+			}
+			providerMap.Set(typ, pt)
+			srcMap.Set(typ, src)
+			overridden.Set(typ, src)
+			defaults.Delete(typ)
+		}
+		if !matched {
+			ec.add(notePosition(fset.Position(o.Pos), fmt.Errorf("wire.Override of %s, but %s does not include a provider for it", typeString(o.types()[0]), setName(set))))
+		}
+	}
+	// Interfaces bound to an overridden concrete type, or to one whose
+	// default was replaced, use its new provider.
+	var rebound []types.Type
+	providerMap.Iterate(func(k types.Type, v interface{}) {
+		t := v.(*ProvidedType).t
+		if types.Identical(k, t) {
+			return
+		}
+		if overridden.At(t) != nil && overridden.At(k) == nil || replaced.At(t) != nil && replaced.At(k) == nil {
+			rebound = append(rebound, k)
+		}
+	})
+	for _, k := range rebound {
+		providerMap.Set(k, providerMap.At(providerMap.At(k).(*ProvidedType).t))
+	}
+	for _, dc := range defaultConflicts {
+		if defaults.At(dc.typ) != nil {
+			ec.add(dc.err)
+		}
+	}
+	if len(ec.errors) > 0 {
+		return nil, nil, nil, nil, ec.errors
 	}
-	return providerMap, srcMap, nil
+	return providerMap, srcMap, defaults, generics, nil
+}
+
+// buildPostConstructMap creates the postConstructs field for a given
+// provider set, whose providerMap must already be built.
+func buildPostConstructMap(fset *token.FileSet, hasher typeutil.Hasher, set *ProviderSet) (*typeutil.Map, []error) {
+	postConstructs := new(typeutil.Map) // to *PostConstruct
+	postConstructs.SetHasher(hasher)
+	ec := new(errorCollector)
+	add := func(typ types.Type, pc *PostConstruct) {
+		if prev, _ := postConstructs.At(typ).(*PostConstruct); prev != nil && prev != pc {
+			ec.add(notePosition(fset.Position(set.Pos), fmt.Errorf("multiple wire.PostConstruct for %s\ncurrent:\n<- %s\nprevious:\n<- %s", typeString(typ), fset.Position(pc.Pos), fset.Position(prev.Pos))))
+			return
+		}
+		postConstructs.Set(typ, pc)
+	}
+	for _, imp := range set.Imports {
+		imp.postConstructs.Iterate(func(typ types.Type, v interface{}) {
+			add(typ, v.(*PostConstruct))
+		})
+	}
+	for _, pc := range set.PostConstructs {
+		pt, _ := set.providerMap.At(pc.Type).(*ProvidedType)
+		provided := pt != nil && pt.IsProvider() && types.Identical(pt.Type(), pc.Type)
+		if pt == nil {
+			_, p, _ := set.instantiate(fset, pc.Type)
+			provided = p != nil
+		}
+		if !provided {
+			ec.add(notePosition(fset.Position(pc.Pos), fmt.Errorf("wire.PostConstruct of %s, but %s does not include a provider function or struct provider for it", typeString(pc.Type), setName(set))))
+			continue
+		}
+		add(pc.Type, pc)
+	}
+	if len(ec.errors) > 0 {
+		return nil, ec.errors
+	}
+	return postConstructs, nil
+}
+
+// buildDecoratorMap creates the decorators field for a given provider set,
+// whose providerMap must already be built.
+func buildDecoratorMap(fset *token.FileSet, hasher typeutil.Hasher, set *ProviderSet) (*typeutil.Map, []error) {
+	decorators := new(typeutil.Map) // to []*Decorator
+	decorators.SetHasher(hasher)
+	add := func(d *Decorator) {
+		prev, _ := decorators.At(d.Type()).([]*Decorator)
+		for _, p := range prev {
+			if p == d {
+				// Imported more than once.
+				return
+			}
+		}
+		decorators.Set(d.Type(), append(prev[:len(prev):len(prev)], d))
+	}
+	for _, imp := range set.Imports {
+		imp.decorators.Iterate(func(_ types.Type, v interface{}) {
+			for _, d := range v.([]*Decorator) {
+				add(d)
+			}
+		})
+	}
+	ec := new(errorCollector)
+	for _, d := range set.Decorators {
+		if set.providerMap.At(d.Type()) == nil {
+			if _, p, _ := set.instantiate(fset, d.Type()); p == nil {
+				ec.add(notePosition(fset.Position(d.Pos), fmt.Errorf("wire.Decorate of %s, but %s does not include a provider for it", typeString(d.Type()), setName(set))))
+				continue
+			}
+		}
+		add(d)
+	}
+	if len(ec.errors) > 0 {
+		return nil, ec.errors
+	}
+	return decorators, nil
+}
+
+// buildInterceptMap creates the intercepts field for a given provider set,
+// whose providerMap must already be built.
+func buildInterceptMap(fset *token.FileSet, hasher typeutil.Hasher, set *ProviderSet) (*typeutil.Map, []error) {
+	intercepts := new(typeutil.Map) // to *Intercept
+	intercepts.SetHasher(hasher)
+	ec := new(errorCollector)
+	add := func(ic *Intercept) {
+		if prev, _ := intercepts.At(ic.Iface).(*Intercept); prev != nil && prev != ic {
+			ec.add(notePosition(fset.Position(set.Pos), fmt.Errorf("multiple wire.Intercept for %s\ncurrent:\n<- %s\nprevious:\n<- %s", typeString(ic.Iface), fset.Position(ic.Pos), fset.Position(prev.Pos))))
+			return
+		}
+		intercepts.Set(ic.Iface, ic)
+	}
+	for _, imp := range set.Imports {
+		imp.intercepts.Iterate(func(_ types.Type, v interface{}) {
+			add(v.(*Intercept))
+		})
+	}
+	for _, ic := range set.Intercepts {
+		pt, _ := set.providerMap.At(ic.Iface).(*ProvidedType)
+		if pt == nil || types.Identical(pt.Type(), ic.Iface) {
+			ec.add(notePosition(fset.Position(ic.Pos), fmt.Errorf("wire.Intercept of %s, but %s does not bind it to a concrete type", typeString(ic.Iface), setName(set))))
+			continue
+		}
+		add(ic)
+	}
+	if len(ec.errors) > 0 {
+		return nil, ec.errors
+	}
+	return intercepts, nil
+}
+
+// buildOptionalMap creates the optionals field for a given provider set.
+func buildOptionalMap(hasher typeutil.Hasher, set *ProviderSet) *typeutil.Map {
+	optionals := new(typeutil.Map) // to *Optional
+	optionals.SetHasher(hasher)
+	for _, imp := range set.Imports {
+		imp.optionals.Iterate(func(typ types.Type, v interface{}) {
+			optionals.Set(typ, v)
+		})
+	}
+	for _, o := range set.Optionals {
+		optionals.Set(o.Type, o)
+	}
+	return optionals
+}
+
+// setName returns the name of set for use in error messages.
+func setName(set *ProviderSet) string {
+	if set.VarName == "" {
+		return "provider set"
+	}
+	return set.VarName
+}
+
+// addContribution adds the contribution c, which comes from src, to the
+// multibinding for its collection type in providerMap.
+func addContribution(fset *token.FileSet, set *ProviderSet, providerMap, srcMap *typeutil.Map, c *Contribution, src *providerSetSrc) error {
+	typ := c.Collection
+	prev := providerMap.At(typ)
+	if prev == nil {
+		m := &Multibinding{
+			Type:          typ,
+			Contributions: []*Contribution{c},
+			srcs:          []*providerSetSrc{src},
+		}
+		providerMap.Set(typ, &ProvidedType{t: typ, m: m})
+		srcMap.Set(typ, src)
+		return nil
+	}
+	m := prev.(*ProvidedType).m
+	if m == nil {
+		return bindingConflictError(fset, typ, set, src, srcMap.At(typ).(*providerSetSrc))
+	}
+	for _, other := range m.Contributions {
+		if other == c {
+			// The same contribution was imported through several sets.
+			return nil
+		}
+		if c.key == nil {
+			continue
+		}
+		key := c.keyInfo.Types[c.key].Value
+		if constant.Compare(key, token.EQL, other.keyInfo.Types[other.key].Value) {
+			sb := new(strings.Builder)
+			if set.VarName != "" {
+				fmt.Fprintf(sb, "%s has ", set.VarName)
+			}
+			fmt.Fprintf(sb, "multiple values for key %s in %s\n", key.ExactString(), typeString(typ))
+			fmt.Fprintf(sb, "current:\n<- %s\n", (&providerSetSrc{Contribution: c}).description(fset, typ))
+			fmt.Fprintf(sb, "previous:\n<- %s", (&providerSetSrc{Contribution: other}).description(fset, typ))
+			return notePosition(fset.Position(set.Pos), errors.New(sb.String()))
+		}
+	}
+	m.Contributions = append(m.Contributions, c)
+	m.srcs = append(m.srcs, src)
+	return nil
 }
 
 func verifyAcyclic(providerMap *typeutil.Map, hasher typeutil.Hasher) []error {
@@ -463,32 +1395,45 @@ func verifyAcyclic(providerMap *typeutil.Map, hasher typeutil.Hasher) []error {
 				// Leaf: values do not have dependencies.
 			case pt.IsArg():
 				// Injector arguments do not have dependencies.
-			case pt.IsProvider() || pt.IsField():
+			case pt.IsProvider() || pt.IsField() || pt.IsMultibinding():
 				var args []types.Type
-				if pt.IsProvider() {
+				switch {
+				case pt.IsProvider():
 					for _, arg := range pt.Provider().Args {
 						args = append(args, arg.Type)
 					}
-				} else {
+				case pt.IsField():
 					args = append(args, pt.Field().Parent)
+				default:
+					for _, c := range pt.Multibinding().Contributions {
+						if c.Provider == nil {
+							continue
+						}
+						for _, arg := range c.Provider.Args {
+							args = append(args, arg.Type)
+						}
+					}
 				}
 				for _, a := range args {
 					hasCycle := false
 					for i, b := range curr {
 						if types.Identical(a, b) {
 							sb := new(strings.Builder)
-							fmt.Fprintf(sb, "cycle for %s:\n", types.TypeString(a, nil))
+							fmt.Fprintf(sb, "cycle for %s:\n", typeString(a))
 							for j := i; j < len(curr); j++ {
 								t := providerMap.At(curr[j]).(*ProvidedType)
-								if t.IsProvider() {
+								switch {
+								case t.IsProvider():
 									p := t.Provider()
-									fmt.Fprintf(sb, "%s (%s.%s) ->\n", types.TypeString(curr[j], nil), p.Pkg.Path(), p.Name)
-								} else {
+									fmt.Fprintf(sb, "%s (%s.%s) ->\n", typeString(curr[j]), p.Pkg.Path(), p.Name)
+								case t.IsField():
 									p := t.Field()
-									fmt.Fprintf(sb, "%s (%s.%s) ->\n", types.TypeString(curr[j], nil), p.Parent, p.Name)
+									fmt.Fprintf(sb, "%s (%s.%s) ->\n", typeString(curr[j]), p.Parent, p.Name)
+								default:
+									fmt.Fprintf(sb, "%s (multibinding) ->\n", typeString(curr[j]))
 								}
 							}
-							fmt.Fprintf(sb, "%s", types.TypeString(a, nil))
+							fmt.Fprintf(sb, "%s", typeString(a))
 							ec.add(errors.New(sb.String()))
 							hasCycle = true
 							break
@@ -514,8 +1459,183 @@ func bindingConflictError(fset *token.FileSet, typ types.Type, set *ProviderSet,
 	if set.VarName != "" {
 		fmt.Fprintf(sb, "%s has ", set.VarName)
 	}
-	fmt.Fprintf(sb, "multiple bindings for %s\n", types.TypeString(typ, nil))
+	fmt.Fprintf(sb, "multiple bindings for %s\n", typeString(typ))
 	fmt.Fprintf(sb, "current:\n<- %s\n", strings.Join(cur.trace(fset, typ), "\n<- "))
 	fmt.Fprintf(sb, "previous:\n<- %s", strings.Join(prev.trace(fset, typ), "\n<- "))
 	return notePosition(fset.Position(set.Pos), errors.New(sb.String()))
 }
+
+// maxInstances is the maximum number of times that a single generic provider
+// may be instantiated while solving for an injector. It guards against
+// generic providers that depend on ever larger instantiations of themselves.
+const maxInstances = 100
+
+// instantiate finds the generic provider in set that can provide t. It
+// returns the generic provider along with its instantiation, or nils if no
+// generic provider in set can provide t.
+func (set *ProviderSet) instantiate(fset *token.FileSet, t types.Type) (*genericProvider, *Provider, error) {
+	var match *genericProvider
+	var inst *Provider
+	for i := range set.genericProviders {
+		gp := &set.genericProviders[i]
+		targs := inferTypeArgs(gp.p.TypeParams, gp.p.Out[0], t)
+		if targs == nil {
+			continue
+		}
+		p, err := instantiateProvider(gp.p, targs)
+		if err != nil {
+			// The inferred type arguments don't satisfy the constraints.
+			continue
+		}
+		if match != nil {
+			return nil, nil, fmt.Errorf("multiple generic providers for %s\ncurrent:\n<- %s\nprevious:\n<- %s",
+				types.TypeString(t, nil), gp.src.description(fset, t), match.src.description(fset, t))
+		}
+		match, inst = gp, p
+	}
+	return match, inst, nil
+}
+
+// inferTypeArgs finds the type arguments for tparams that make the pattern
+// type identical to t. It returns nil if there are no such type arguments.
+func inferTypeArgs(tparams *types.TypeParamList, pattern, t types.Type) []types.Type {
+	targs := make([]types.Type, tparams.Len())
+	if !unify(tparams, targs, pattern, t) {
+		return nil
+	}
+	for _, targ := range targs {
+		if targ == nil {
+			return nil
+		}
+	}
+	return targs
+}
+
+// unify reports whether x can be made identical to y by substituting type
+// arguments for the type parameters in tparams that x refers to. targs holds
+// the type arguments found so far, indexed like tparams; unify fills in the
+// type arguments that it finds.
+func unify(tparams *types.TypeParamList, targs []types.Type, x, y types.Type) bool {
+	if tp, ok := x.(*types.TypeParam); ok {
+		for i := 0; i < tparams.Len(); i++ {
+			if tparams.At(i) != tp {
+				continue
+			}
+			if targs[i] == nil {
+				targs[i] = y
+				return true
+			}
+			return types.Identical(targs[i], y)
+		}
+	}
+	switch x := x.(type) {
+	case *types.Pointer:
+		y, ok := y.(*types.Pointer)
+		return ok && unify(tparams, targs, x.Elem(), y.Elem())
+	case *types.Slice:
+		y, ok := y.(*types.Slice)
+		return ok && unify(tparams, targs, x.Elem(), y.Elem())
+	case *types.Array:
+		y, ok := y.(*types.Array)
+		return ok && x.Len() == y.Len() && unify(tparams, targs, x.Elem(), y.Elem())
+	case *types.Map:
+		y, ok := y.(*types.Map)
+		return ok && unify(tparams, targs, x.Key(), y.Key()) && unify(tparams, targs, x.Elem(), y.Elem())
+	case *types.Chan:
+		y, ok := y.(*types.Chan)
+		return ok && x.Dir() == y.Dir() && unify(tparams, targs, x.Elem(), y.Elem())
+	case *types.Named:
+		y, ok := y.(*types.Named)
+		if !ok || x.Obj() != y.Obj() {
+			return false
+		}
+		xargs, yargs := x.TypeArgs(), y.TypeArgs()
+		if xargs.Len() != yargs.Len() {
+			return false
+		}
+		for i := 0; i < xargs.Len(); i++ {
+			if !unify(tparams, targs, xargs.At(i), yargs.At(i)) {
+				return false
+			}
+		}
+		return true
+	case *types.Signature:
+		y, ok := y.(*types.Signature)
+		return ok && x.Variadic() == y.Variadic() &&
+			unifyTuple(tparams, targs, x.Params(), y.Params()) &&
+			unifyTuple(tparams, targs, x.Results(), y.Results())
+	case *types.Struct:
+		y, ok := y.(*types.Struct)
+		if !ok || x.NumFields() != y.NumFields() {
+			return false
+		}
+		for i := 0; i < x.NumFields(); i++ {
+			xf, yf := x.Field(i), y.Field(i)
+			if xf.Name() != yf.Name() || xf.Embedded() != yf.Embedded() || x.Tag(i) != y.Tag(i) || !unify(tparams, targs, xf.Type(), yf.Type()) {
+				return false
+			}
+		}
+		return true
+	default:
+		// Other types, like interfaces, are only unified if they are
+		// already identical.
+		return types.Identical(x, y)
+	}
+}
+
+func unifyTuple(tparams *types.TypeParamList, targs []types.Type, x, y *types.Tuple) bool {
+	if x.Len() != y.Len() {
+		return false
+	}
+	for i := 0; i < x.Len(); i++ {
+		if !unify(tparams, targs, x.At(i).Type(), y.At(i).Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+// mentionsTypeParam reports whether tp occurs in t in a position where
+// unify can infer it.
+func mentionsTypeParam(t types.Type, tp *types.TypeParam) bool {
+	switch t := t.(type) {
+	case *types.TypeParam:
+		return t == tp
+	case *types.Pointer:
+		return mentionsTypeParam(t.Elem(), tp)
+	case *types.Slice:
+		return mentionsTypeParam(t.Elem(), tp)
+	case *types.Array:
+		return mentionsTypeParam(t.Elem(), tp)
+	case *types.Map:
+		return mentionsTypeParam(t.Key(), tp) || mentionsTypeParam(t.Elem(), tp)
+	case *types.Chan:
+		return mentionsTypeParam(t.Elem(), tp)
+	case *types.Named:
+		targs := t.TypeArgs()
+		for i := 0; i < targs.Len(); i++ {
+			if mentionsTypeParam(targs.At(i), tp) {
+				return true
+			}
+		}
+		return false
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{t.Params(), t.Results()} {
+			for i := 0; i < tuple.Len(); i++ {
+				if mentionsTypeParam(tuple.At(i).Type(), tp) {
+					return true
+				}
+			}
+		}
+		return false
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if mentionsTypeParam(t.Field(i).Type(), tp) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"go/types"
 	"os"
@@ -34,12 +35,19 @@ import (
 // A providerSetSrc captures the source for a type provided by a ProviderSet.
 // Exactly one of the fields will be set.
 type providerSetSrc struct {
-	Provider    *Provider
-	Binding     *IfaceBinding
-	Value       *Value
-	Import      *ProviderSet
-	InjectorArg *InjectorArg
-	Field       *Field
+	Provider      *Provider
+	Binding       *IfaceBinding
+	Value         *Value
+	Import        *ProviderSet
+	InjectorArg   *InjectorArg
+	Field         *Field
+	Contribution  *Contribution
+	Override      *Override
+	Default       *Default
+	PostConstruct *PostConstruct
+	Decorator     *Decorator
+	Intercept     *Intercept
+	Optional      *Optional
 }
 
 // description returns a string describing the source of p, including line numbers.
@@ -53,8 +61,13 @@ func (p *providerSetSrc) description(fset *token.FileSet, typ types.Type) string
 	switch {
 	case p.Provider != nil:
 		kind := "provider"
-		if p.Provider.IsStruct {
+		switch {
+		case p.Provider.IsStruct:
 			kind = "struct provider"
+		case p.Provider.Factory != nil:
+			kind = "wire.Factory of"
+		case p.Provider.Select != nil:
+			kind = "wire.Select by"
 		}
 		return fmt.Sprintf("%s %s(%s)", kind, quoted(p.Provider.Name), fset.Position(p.Provider.Pos))
 	case p.Binding != nil:
@@ -68,6 +81,23 @@ func (p *providerSetSrc) description(fset *token.FileSet, typ types.Type) string
 		return fmt.Sprintf("argument %s to injector function %s (%s)", args.Tuple.At(p.InjectorArg.Index).Name(), args.Name, fset.Position(args.Pos))
 	case p.Field != nil:
 		return fmt.Sprintf("wire.FieldsOf (%s)", fset.Position(p.Field.Pos))
+	case p.Contribution != nil:
+		if p.Contribution.key != nil {
+			return fmt.Sprintf("wire.IntoMap (%s)", fset.Position(p.Contribution.Pos))
+		}
+		return fmt.Sprintf("wire.Into (%s)", fset.Position(p.Contribution.Pos))
+	case p.Override != nil:
+		return fmt.Sprintf("wire.Override (%s)", fset.Position(p.Override.Pos))
+	case p.Default != nil:
+		return fmt.Sprintf("wire.Default (%s)", fset.Position(p.Default.Pos))
+	case p.PostConstruct != nil:
+		return fmt.Sprintf("wire.PostConstruct (%s)", fset.Position(p.PostConstruct.Pos))
+	case p.Decorator != nil:
+		return fmt.Sprintf("wire.Decorate (%s)", fset.Position(p.Decorator.Pos))
+	case p.Intercept != nil:
+		return fmt.Sprintf("wire.Intercept (%s)", fset.Position(p.Intercept.Pos))
+	case p.Optional != nil:
+		return fmt.Sprintf("wire.Optional (%s)", fset.Position(p.Optional.Pos))
 	}
 	panic("providerSetSrc with no fields set")
 }
@@ -81,6 +111,11 @@ func (p *providerSetSrc) trace(fset *token.FileSet, typ types.Type) []string {
 		if parent := p.Import.srcMap.At(typ); parent != nil {
 			retval = append(retval, parent.(*providerSetSrc).trace(fset, typ)...)
 		}
+		for _, gp := range p.Import.genericProviders {
+			if types.Identical(gp.p.Out[0], typ) {
+				retval = append(retval, gp.src.trace(fset, typ)...)
+			}
+		}
 	}
 	retval = append(retval, p.description(fset, typ))
 	return retval
@@ -103,8 +138,31 @@ type ProviderSet struct {
 	Values    []*Value
 	Fields    []*Field
 	Imports   []*ProviderSet
+	// Contributions are the elements added to slice and map types by
+	// wire.Into and wire.IntoMap.
+	Contributions []*Contribution
+	// Overrides replace the providers of types from the rest of the set,
+	// including its imports.
+	Overrides []*Override
+	// Defaults provide types only if nothing else in the final set
+	// provides them.
+	Defaults []*Default
+	// PostConstructs are the methods called on values of the set's types
+	// right after they are provided.
+	PostConstructs []*PostConstruct
+	// Decorators wrap the values of the set's types after they are
+	// provided.
+	Decorators []*Decorator
+	// Intercepts wrap the values of the set's interface types in proxies
+	// that call an interceptor.
+	Intercepts []*Intercept
+	// Optionals are the types whose zero values are used if they aren't
+	// provided.
+	Optionals []*Optional
 	// InjectorArgs is only filled in for wire.Build.
 	InjectorArgs *InjectorArgs
+	// Options holds the injector options passed to wire.Build.
+	Options InjectorOptions
 
 	// providerMap maps from provided type to a *ProvidedType.
 	// It includes all of the imported types.
@@ -113,18 +171,64 @@ type ProviderSet struct {
 	// srcMap maps from provided type to a *providerSetSrc capturing the
 	// Provider, Binding, Value, or Import that provided the type.
 	srcMap *typeutil.Map
+
+	// defaults maps from a type provided by a default to its
+	// *providerSetSrc. Such types may still be provided by an importing
+	// set, which replaces the default.
+	defaults *typeutil.Map
+
+	// postConstructs maps from provided type to the *PostConstruct for it.
+	// It includes the post-construct methods of the imported sets.
+	postConstructs *typeutil.Map
+
+	// decorators maps from provided type to the []*Decorator for it, in
+	// the order they are applied. It includes the decorators of the
+	// imported sets, which are applied first.
+	decorators *typeutil.Map
+
+	// intercepts maps from interface type to the *Intercept for it. It
+	// includes the intercepts of the imported sets.
+	intercepts *typeutil.Map
+
+	// optionals maps from type to the *Optional for it. It includes the
+	// optional types of the imported sets.
+	optionals *typeutil.Map
+
+	// genericProviders holds the generic provider functions in the set,
+	// including those from imported sets. They are instantiated on demand
+	// during solve, so their outputs are not in providerMap.
+	genericProviders []genericProvider
+}
+
+// A genericProvider is a generic provider function along with the
+// providerSetSrc that brought it into a ProviderSet.
+type genericProvider struct {
+	p   *Provider
+	src *providerSetSrc
 }
 
 // Outputs returns a new slice containing the set of possible types the
-// provider set can produce. The order is unspecified.
+// provider set can produce. The outputs of generic providers are included
+// with their type parameters, like *Repo[T]. The order is unspecified.
 func (set *ProviderSet) Outputs() []types.Type {
-	return set.providerMap.Keys()
+	outs := set.providerMap.Keys()
+	for _, gp := range set.genericProviders {
+		outs = append(outs, gp.p.Out[0])
+	}
+	return outs
 }
 
 // For returns a ProvidedType for the given type, or the zero ProvidedType.
+// For the output type of a generic provider, as returned by Outputs, it
+// returns the generic provider.
 func (set *ProviderSet) For(t types.Type) ProvidedType {
 	pt := set.providerMap.At(t)
 	if pt == nil {
+		for _, gp := range set.genericProviders {
+			if types.Identical(gp.p.Out[0], t) {
+				return ProvidedType{t: t, p: gp.p}
+			}
+		}
 		return ProvidedType{}
 	}
 	return *pt.(*ProvidedType)
@@ -174,9 +278,41 @@ type Provider struct {
 	// function.  (Always false for structs.)
 	HasCleanup bool
 
+	// Cleanup is the signature of the cleanup function that the provider
+	// function returns, if HasCleanup is true.
+	Cleanup CleanupKind
+
+	// AutoClose reports whether the provider came from a call to
+	// wire.AutoClose. Its cleanup function is the Close method of the
+	// provided value rather than a return value of the function.
+	AutoClose bool
+
+	// Factory is set if the provider came from a call to wire.Factory. The
+	// provider's output is then the factory function, which calls
+	// Factory.Provider.
+	Factory *Factory
+
+	// Select is set if the provider came from a call to wire.Select. The
+	// provider's Args are then the arguments of Select.Key followed by the
+	// types in Select.Free that aren't among them.
+	Select *Select
+
 	// HasErr reports whether the provider function can return an error.
 	// (Always false for structs.)
 	HasErr bool
+
+	// TypeParams is the list of type parameters of a generic provider
+	// function that has not been instantiated, or nil otherwise. Args and
+	// Out may refer to these type parameters.
+	TypeParams *types.TypeParamList
+
+	// TypeArgs is the list of type arguments used to instantiate a generic
+	// provider function, or nil if the provider is not an instantiation.
+	TypeArgs []types.Type
+
+	// sig is the signature of a generic provider function. It is used to
+	// instantiate the provider.
+	sig *types.Signature
 }
 
 // ProviderInput describes an incoming edge in the provider graph.
@@ -200,6 +336,10 @@ type Value struct {
 
 	// info is the type info for the expression.
 	info *types.Info
+
+	// typed is true if the value is declared with type Out in the generated
+	// code rather than with the expression's type, as for wire.ValueOf.
+	typed bool
 }
 
 // InjectorArg describes a specific argument passed to an injector function.
@@ -237,6 +377,207 @@ type Field struct {
 	Out []types.Type
 }
 
+// A Contribution is an element added to a slice or map type by a call to
+// wire.Into or wire.IntoMap.
+type Contribution struct {
+	// Pos is the source position of the call to wire.Into or wire.IntoMap.
+	Pos token.Pos
+	// Collection is the slice or map type that the element is added to.
+	Collection types.Type
+	// Out is the type of the element before it is added to Collection.
+	Out types.Type
+	// Exactly one of Provider and Value produces the element.
+	Provider *Provider
+	Value    *Value
+
+	// key is the constant map key expression passed to wire.IntoMap, or nil
+	// for wire.Into.
+	key ast.Expr
+	// keyInfo is the type info for key.
+	keyInfo *types.Info
+}
+
+// InjectorOptions control how an injector is generated. They may be set for
+// every injector with GenerateOptions, or for one injector by passing
+// markers like wire.RecoverPanics to its wire.Build call.
+type InjectorOptions struct {
+	// RecoverPanics causes the injector to call the cleanup functions of
+	// the values built so far if a provider panics, then panic again.
+	RecoverPanics bool
+	// PanicsAsErrors is like RecoverPanics, but an injector that returns
+	// an error returns the panic as its error instead of panicking again.
+	PanicsAsErrors bool
+	// Parallel causes the injector to call provider functions that don't
+	// depend on each other concurrently.
+	Parallel bool
+	// CheckContext causes an injector that returns an error and takes a
+	// context.Context to check whether the context is done between
+	// provider function calls.
+	CheckContext bool
+	// WrapErrors causes the injector to wrap the errors returned by
+	// provider functions in a *wireruntime.ProviderError.
+	WrapErrors bool
+}
+
+// merge returns the options that are set in either opts or other.
+func (opts InjectorOptions) merge(other InjectorOptions) InjectorOptions {
+	return InjectorOptions{
+		RecoverPanics:  opts.RecoverPanics || other.RecoverPanics,
+		PanicsAsErrors: opts.PanicsAsErrors || other.PanicsAsErrors,
+		Parallel:       opts.Parallel || other.Parallel,
+		CheckContext:   opts.CheckContext || other.CheckContext,
+		WrapErrors:     opts.WrapErrors || other.WrapErrors,
+	}
+}
+
+// An injectorOption is a marker passed to wire.Build that sets one of the
+// injector's options.
+type injectorOption struct {
+	// name is the name of the marker function.
+	name string
+	set  func(*InjectorOptions)
+}
+
+// An Override replaces the provider of a type in a provider set, including
+// one provided by an imported set, with the provider, value or interface
+// binding passed to wire.Override.
+type Override struct {
+	// Pos is the source position of the call to wire.Override.
+	Pos token.Pos
+	// Exactly one of Provider, Value and Binding is set.
+	Provider *Provider
+	Value    *Value
+	Binding  *IfaceBinding
+}
+
+// A Default provides a type in a provider set only if nothing else in the
+// final set provides it, set up by wire.Default. It has the same fields as
+// an Override.
+type Default struct {
+	// Pos is the source position of the call to wire.Default.
+	Pos token.Pos
+	// Exactly one of Provider, Value and Binding is set.
+	Provider *Provider
+	Value    *Value
+	Binding  *IfaceBinding
+}
+
+// A PostConstruct is a method called on a value right after a provider
+// function or struct provider produces it, set up by wire.PostConstruct.
+type PostConstruct struct {
+	// Pos is the source position of the call to wire.PostConstruct.
+	Pos token.Pos
+	// Type is the type of the values that the method is called on.
+	Type types.Type
+	// Method is the name of the method, either "Validate" or "Init".
+	Method string
+}
+
+// A Decorator is a provider function that wraps a provided value, set up by
+// wire.Decorate. Its first argument is the value to wrap, which has the same
+// type as its output.
+type Decorator struct {
+	// Pos is the source position of the call to wire.Decorate.
+	Pos token.Pos
+	// Provider is the decorator function.
+	Provider *Provider
+}
+
+// Type returns the type of the values that d wraps.
+func (d *Decorator) Type() types.Type {
+	return d.Provider.Out[0]
+}
+
+// An Intercept wraps the values of an interface type in a generated proxy
+// that calls a wireruntime.Interceptor around every method call, set up by
+// wire.Intercept.
+type Intercept struct {
+	// Pos is the source position of the call to wire.Intercept.
+	Pos token.Pos
+	// Iface is the intercepted interface type.
+	Iface types.Type
+	// Interceptor is the wireruntime.Interceptor type, which the proxy
+	// depends on.
+	Interceptor types.Type
+}
+
+// A Select chooses between provider sets by a key computed when the injector
+// runs, set up by wire.Select.
+type Select struct {
+	// Key is the provider function that returns the key.
+	Key *Provider
+	// Branches are the provider sets to choose from, in source order.
+	Branches []*ProviderSet
+	// Free is the list of types that the branches need but don't provide.
+	// They are provided by the enclosing set.
+	Free []types.Type
+
+	// keys are the constant map keys of the branches.
+	keys []ast.Expr
+	// keyInfo is the type info for keys.
+	keyInfo *types.Info
+	// calls holds the calls of each branch, whose givens are Free.
+	calls [][]call
+	// outs holds the index of the provided value in each branch.
+	outs []int
+	// freeArgs holds the index in the provider's Args of each type in Free.
+	freeArgs []int
+}
+
+// An Optional is a type whose zero value is used if it isn't provided, set
+// up by wire.Optional.
+type Optional struct {
+	// Pos is the source position of the call to wire.Optional.
+	Pos token.Pos
+	// Type is the optional type.
+	Type types.Type
+}
+
+// A Factory is a function that calls a provider function with arguments
+// passed at call time, set up by wire.Factory. It is provided by a Provider
+// whose Args are the arguments of the provider function that are not passed
+// at call time.
+type Factory struct {
+	// Provider is the provider function that the factory function calls.
+	Provider *Provider
+	// Sig is the signature of the factory function.
+	Sig *types.Signature
+	// Params holds, for each argument of Provider, the index of the
+	// parameter of the factory function passed to it, or -1 if the argument
+	// is provided by the enclosing set.
+	Params []int
+}
+
+// types returns the types that o provides.
+func (o *Override) types() []types.Type {
+	switch {
+	case o.Provider != nil:
+		return o.Provider.Out
+	case o.Value != nil:
+		return []types.Type{o.Value.Out}
+	default:
+		return []types.Type{o.Binding.Iface}
+	}
+}
+
+// types returns the types that d provides.
+func (d *Default) types() []types.Type {
+	return (*Override)(d).types()
+}
+
+// A Multibinding is a slice or map type provided by collecting the elements
+// contributed to it with wire.Into or wire.IntoMap.
+type Multibinding struct {
+	// Type is the slice or map type.
+	Type types.Type
+	// Contributions are the contributed elements, in order.
+	Contributions []*Contribution
+
+	// srcs are the sources of Contributions in the provider set that the
+	// multibinding belongs to.
+	srcs []*providerSetSrc
+}
+
 // Load finds all the provider sets in the packages that match the given
 // patterns, as well as the provider sets' transitive dependencies. It
 // may return both errors and Info. The patterns are defined by the
@@ -319,7 +660,8 @@ func Load(ctx context.Context, wd string, env []string, tags string, patterns []
 					ec.add(notePositionAll(fset.Position(fn.Pos()), errs)...)
 					continue
 				}
-				_, errs = solve(fset, out.out, ins, set)
+				want, _ := appType(out.out)
+				_, errs = solve(fset, want, ins, set)
 				if len(errs) > 0 {
 					ec.add(mapErrors(errs, func(e error) error {
 						if w, ok := e.(*wireErr); ok {
@@ -421,6 +763,10 @@ type objectCache struct {
 	packages map[string]*packages.Package
 	objects  map[objRef]objCacheEntry
 	hasher   typeutil.Hasher
+
+	// qualifiers maps names given to wire.Named to the generic types used to
+	// represent qualified types. See qualifiedType.
+	qualifiers map[string]*types.Named
 }
 
 type objRef struct {
@@ -442,6 +788,8 @@ func newObjectCache(pkgs []*packages.Package) *objectCache {
 		packages: make(map[string]*packages.Package),
 		objects:  make(map[objRef]objCacheEntry),
 		hasher:   typeutil.MakeHasher(),
+
+		qualifiers: make(map[string]*types.Named),
 	}
 	// Depth-first search of all dependencies to gather import path to
 	// packages.Package mapping. go/packages guarantees that for a single
@@ -520,7 +868,8 @@ func (oc *objectCache) varDecl(obj *types.Var) *ast.ValueSpec {
 }
 
 // processExpr converts an expression into a Wire structure. It may return a
-// *Provider, an *IfaceBinding, a *ProviderSet, a *Value or a []*Field.
+// *Provider, an *IfaceBinding, a *ProviderSet, a *Value, a []*Field or a
+// *Contribution.
 func (oc *objectCache) processExpr(info *types.Info, pkgPath string, expr ast.Expr, varName string) (interface{}, []error) {
 	exprPos := oc.fset.Position(expr.Pos())
 	expr = astutil.Unparen(expr)
@@ -530,8 +879,13 @@ func (oc *objectCache) processExpr(info *types.Info, pkgPath string, expr ast.Ex
 			return notePosition(exprPos, err)
 		})
 	}
+	if p, errs := oc.processInstantiatedFuncProvider(info, expr); len(errs) > 0 {
+		return nil, notePositionAll(exprPos, errs)
+	} else if p != nil {
+		return p, nil
+	}
 	if call, ok := expr.(*ast.CallExpr); ok {
-		fnObj := qualifiedIdentObject(info, call.Fun)
+		fnObj := qualifiedIdentObject(info, instanceBase(call.Fun))
 		if fnObj == nil {
 			return nil, []error{notePosition(exprPos, errors.New("unknown pattern fnObj nil"))}
 		}
@@ -552,12 +906,24 @@ func (oc *objectCache) processExpr(info *types.Info, pkgPath string, expr ast.Ex
 				return nil, []error{notePosition(exprPos, err)}
 			}
 			return b, nil
+		case "BindTo":
+			b, err := processBindTo(oc.fset, info, call)
+			if err != nil {
+				return nil, []error{notePosition(exprPos, err)}
+			}
+			return b, nil
 		case "Value":
 			v, err := processValue(oc.fset, info, call)
 			if err != nil {
 				return nil, []error{notePosition(exprPos, err)}
 			}
 			return v, nil
+		case "ValueOf":
+			v, err := processValueOf(oc.fset, info, call)
+			if err != nil {
+				return nil, []error{notePosition(exprPos, err)}
+			}
+			return v, nil
 		case "InterfaceValue":
 			v, err := processInterfaceValue(oc.fset, info, call)
 			if err != nil {
@@ -565,7 +931,13 @@ func (oc *objectCache) processExpr(info *types.Info, pkgPath string, expr ast.Ex
 			}
 			return v, nil
 		case "Struct":
-			s, err := processStructProvider(oc.fset, info, call)
+			s, err := oc.processStructProvider(info, call)
+			if err != nil {
+				return nil, []error{notePosition(exprPos, err)}
+			}
+			return s, nil
+		case "StructOf":
+			s, err := oc.processStructOf(info, call)
 			if err != nil {
 				return nil, []error{notePosition(exprPos, err)}
 			}
@@ -576,6 +948,96 @@ func (oc *objectCache) processExpr(info *types.Info, pkgPath string, expr ast.Ex
 				return nil, []error{notePosition(exprPos, err)}
 			}
 			return v, nil
+		case "FieldsFrom":
+			v, err := processFieldsFrom(oc.fset, info, call)
+			if err != nil {
+				return nil, []error{notePosition(exprPos, err)}
+			}
+			return v, nil
+		case "Named":
+			item, errs := oc.processNamed(info, pkgPath, call)
+			if len(errs) > 0 {
+				return nil, notePositionAll(exprPos, errs)
+			}
+			return item, nil
+		case "NamedParams":
+			p, errs := oc.processNamedParams(info, call)
+			if len(errs) > 0 {
+				return nil, notePositionAll(exprPos, errs)
+			}
+			return p, nil
+		case "Into", "IntoMap":
+			c, errs := oc.processInto(info, pkgPath, call, fnObj.Name())
+			if len(errs) > 0 {
+				return nil, notePositionAll(exprPos, errs)
+			}
+			return c, nil
+		case "Override":
+			o, errs := oc.processOverride(info, pkgPath, call, fnObj.Name())
+			if len(errs) > 0 {
+				return nil, notePositionAll(exprPos, errs)
+			}
+			return o, nil
+		case "Default":
+			o, errs := oc.processOverride(info, pkgPath, call, fnObj.Name())
+			if len(errs) > 0 {
+				return nil, notePositionAll(exprPos, errs)
+			}
+			return (*Default)(o), nil
+		case "Intercept":
+			ic, err := processIntercept(oc.fset, info, pkgPath, oc.packages["github.com/google/wire/wireruntime"], call)
+			if err != nil {
+				return nil, []error{notePosition(exprPos, err)}
+			}
+			return ic, nil
+		case "Decorate":
+			d, errs := oc.processDecorate(info, pkgPath, call)
+			if len(errs) > 0 {
+				return nil, notePositionAll(exprPos, errs)
+			}
+			return d, nil
+		case "Optional":
+			o, err := processOptional(oc.fset, info, call)
+			if err != nil {
+				return nil, []error{notePosition(exprPos, err)}
+			}
+			return o, nil
+		case "PostConstruct":
+			pc, err := processPostConstruct(oc.fset, info, call)
+			if err != nil {
+				return nil, []error{notePosition(exprPos, err)}
+			}
+			return pc, nil
+		case "AutoClose":
+			p, errs := oc.processAutoClose(info, pkgPath, call)
+			if len(errs) > 0 {
+				return nil, notePositionAll(exprPos, errs)
+			}
+			return p, nil
+		case "Factory":
+			p, errs := oc.processFactory(info, pkgPath, call)
+			if len(errs) > 0 {
+				return nil, notePositionAll(exprPos, errs)
+			}
+			return p, nil
+		case "Select":
+			p, errs := oc.processSelect(info, pkgPath, call)
+			if len(errs) > 0 {
+				return nil, notePositionAll(exprPos, errs)
+			}
+			return p, nil
+		case "RecoverPanics", "PanicsAsErrors", "Parallel", "CheckContext", "WrapErrors":
+			opt, err := processInjectorOption(call, fnObj.Name())
+			if err != nil {
+				return nil, []error{notePosition(exprPos, err)}
+			}
+			return opt, nil
+		case "Generic":
+			p, errs := oc.processGeneric(info, call)
+			if len(errs) > 0 {
+				return nil, notePositionAll(exprPos, errs)
+			}
+			return p, nil
 		default:
 			return nil, []error{notePosition(exprPos, errors.New("unknown pattern"))}
 		}
@@ -607,6 +1069,12 @@ func (oc *objectCache) processNewSet(info *types.Info, pkgPath string, call *ast
 			continue
 		}
 		switch item := item.(type) {
+		case *injectorOption:
+			if args == nil {
+				ec.add(notePosition(oc.fset.Position(arg.Pos()), fmt.Errorf("wire.%s may only be passed to wire.Build", item.name)))
+				continue
+			}
+			item.set(&pset.Options)
 		case *Provider:
 			pset.Providers = append(pset.Providers, item)
 		case *ProviderSet:
@@ -617,6 +1085,20 @@ func (oc *objectCache) processNewSet(info *types.Info, pkgPath string, call *ast
 			pset.Values = append(pset.Values, item)
 		case []*Field:
 			pset.Fields = append(pset.Fields, item...)
+		case *Contribution:
+			pset.Contributions = append(pset.Contributions, item)
+		case *Override:
+			pset.Overrides = append(pset.Overrides, item)
+		case *Default:
+			pset.Defaults = append(pset.Defaults, item)
+		case *PostConstruct:
+			pset.PostConstructs = append(pset.PostConstructs, item)
+		case *Decorator:
+			pset.Decorators = append(pset.Decorators, item)
+		case *Intercept:
+			pset.Intercepts = append(pset.Intercepts, item)
+		case *Optional:
+			pset.Optionals = append(pset.Optionals, item)
 		default:
 			panic("unknown item type")
 		}
@@ -625,13 +1107,26 @@ func (oc *objectCache) processNewSet(info *types.Info, pkgPath string, call *ast
 		return nil, ec.errors
 	}
 	var errs []error
-	pset.providerMap, pset.srcMap, errs = buildProviderMap(oc.fset, oc.hasher, pset)
+	pset.providerMap, pset.srcMap, pset.defaults, pset.genericProviders, errs = buildProviderMap(oc.fset, oc.hasher, pset)
 	if len(errs) > 0 {
 		return nil, errs
 	}
 	if errs := verifyAcyclic(pset.providerMap, oc.hasher); len(errs) > 0 {
 		return nil, errs
 	}
+	pset.postConstructs, errs = buildPostConstructMap(oc.fset, oc.hasher, pset)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	pset.decorators, errs = buildDecoratorMap(oc.fset, oc.hasher, pset)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	pset.intercepts, errs = buildInterceptMap(oc.fset, oc.hasher, pset)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	pset.optionals = buildOptionalMap(oc.hasher, pset)
 	return pset, nil
 }
 
@@ -652,6 +1147,20 @@ func structArgType(info *types.Info, expr ast.Expr) *types.TypeName {
 	return tn
 }
 
+// instanceBase returns the generic function or type named in an
+// instantiation expression like Cache[string] or Pair[K, V], or expr itself
+// if it is not an instantiation.
+func instanceBase(expr ast.Expr) ast.Expr {
+	switch x := expr.(type) {
+	case *ast.IndexExpr:
+		return x.X
+	case *ast.IndexListExpr:
+		return x.X
+	default:
+		return expr
+	}
+}
+
 // qualifiedIdentObject finds the object for an identifier or a
 // qualified identifier, or nil if the object could not be found.
 func qualifiedIdentObject(info *types.Info, expr ast.Expr) types.Object {
@@ -675,131 +1184,972 @@ func qualifiedIdentObject(info *types.Info, expr ast.Expr) types.Object {
 // processFuncProvider creates a provider for a function declaration.
 func processFuncProvider(fset *token.FileSet, fn *types.Func) (*Provider, []error) {
 	sig := fn.Type().(*types.Signature)
-	fpos := fn.Pos()
+	provider, err := newFuncProvider(fn.Pkg(), fn.Name(), fn.Pos(), sig)
+	if err != nil {
+		return nil, []error{notePosition(fset.Position(fn.Pos()), fmt.Errorf("wrong signature for provider %s: %v", fn.Name(), err))}
+	}
+	if tparams := sig.TypeParams(); tparams.Len() > 0 {
+		provider.TypeParams = tparams
+		provider.sig = sig
+	}
+	if err := verifyDistinctArgs(provider); err != nil {
+		return nil, []error{notePosition(fset.Position(fn.Pos()), err)}
+	}
+	return provider, nil
+}
+
+// instantiateProvider creates a provider for the instantiation of the
+// generic provider p with the given type arguments.
+func instantiateProvider(p *Provider, targs []types.Type) (*Provider, error) {
+	inst, err := types.Instantiate(nil, p.sig, targs, true)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := newFuncProvider(p.Pkg, p.Name, p.Pos, inst.(*types.Signature))
+	if err != nil {
+		return nil, err
+	}
+	provider.TypeArgs = targs
+	if err := verifyDistinctArgs(provider); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// newFuncProvider creates a provider for a function with the given
+// signature. It does not verify that the function's parameters have
+// distinct types.
+func newFuncProvider(pkg *types.Package, name string, pos token.Pos, sig *types.Signature) (*Provider, error) {
 	providerSig, err := funcOutput(sig)
 	if err != nil {
-		return nil, []error{notePosition(fset.Position(fpos), fmt.Errorf("wrong signature for provider %s: %v", fn.Name(), err))}
+		return nil, err
 	}
 	params := sig.Params()
 	provider := &Provider{
-		Pkg:        fn.Pkg(),
-		Name:       fn.Name(),
-		Pos:        fn.Pos(),
+		Pkg:        pkg,
+		Name:       name,
+		Pos:        pos,
 		Args:       make([]ProviderInput, params.Len()),
 		Varargs:    sig.Variadic(),
 		Out:        []types.Type{providerSig.out},
-		HasCleanup: providerSig.cleanup,
+		HasCleanup: providerSig.cleanup != NoCleanup,
+		Cleanup:    providerSig.cleanup,
 		HasErr:     providerSig.err,
 	}
 	for i := 0; i < params.Len(); i++ {
 		provider.Args[i] = ProviderInput{
 			Type: params.At(i).Type(),
 		}
+	}
+	return provider, nil
+}
+
+// verifyDistinctArgs ensures that no two parameters of the provider function
+// p have identical types, since Wire could not tell them apart.
+func verifyDistinctArgs(p *Provider) error {
+	for i := range p.Args {
 		for j := 0; j < i; j++ {
-			if types.Identical(provider.Args[i].Type, provider.Args[j].Type) {
-				return nil, []error{notePosition(fset.Position(fpos), fmt.Errorf("provider has multiple parameters of type %s", types.TypeString(provider.Args[j].Type, nil)))}
+			if types.Identical(p.Args[i].Type, p.Args[j].Type) {
+				return fmt.Errorf("provider has multiple parameters of type %s", typeString(p.Args[j].Type))
 			}
 		}
 	}
-	return provider, nil
+	return nil
 }
 
-func injectorFuncSignature(sig *types.Signature) (*types.Tuple, outputSignature, error) {
-	out, err := funcOutput(sig)
+// instantiatedFunc interprets an expression as an explicit instantiation of a
+// generic function, like NewRepo[User]. It returns nil if expr is not one.
+func instantiatedFunc(info *types.Info, expr ast.Expr) (*types.Func, []types.Type) {
+	fun := instanceBase(expr)
+	if fun == expr {
+		return nil, nil
+	}
+	fn, ok := qualifiedIdentObject(info, fun).(*types.Func)
+	if !ok {
+		return nil, nil
+	}
+	return fn, funcTypeArgs(info, expr)
+}
+
+// funcTypeArgs returns the type arguments, explicit or inferred, of a
+// reference to a generic function. fun must be an identifier or a qualified
+// identifier, optionally followed by type arguments.
+func funcTypeArgs(info *types.Info, fun ast.Expr) []types.Type {
+	id, ok := instanceBase(fun).(*ast.Ident)
+	if !ok {
+		id = instanceBase(fun).(*ast.SelectorExpr).Sel
+	}
+	targList := info.Instances[id].TypeArgs
+	targs := make([]types.Type, targList.Len())
+	for i := range targs {
+		targs[i] = targList.At(i)
+	}
+	return targs
+}
+
+// processInstantiatedFuncProvider creates a provider for an explicit
+// instantiation of a generic function, like NewRepo[User]. It returns nil
+// if expr is not an instantiation.
+func (oc *objectCache) processInstantiatedFuncProvider(info *types.Info, expr ast.Expr) (*Provider, []error) {
+	fn, targs := instantiatedFunc(info, expr)
+	if fn == nil {
+		return nil, nil
+	}
+	item, errs := oc.get(fn)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	inst, err := instantiateProvider(item.(*Provider), targs)
 	if err != nil {
-		return nil, outputSignature{}, err
+		return nil, []error{notePosition(oc.fset.Position(expr.Pos()), fmt.Errorf("wrong signature for provider %s: %v", fn.Name(), err))}
 	}
-	return sig.Params(), out, nil
+	return inst, nil
 }
 
-type outputSignature struct {
-	out     types.Type
-	cleanup bool
-	err     bool
+// processGeneric creates a generic provider from a wire.Generic call.
+func (oc *objectCache) processGeneric(info *types.Info, call *ast.CallExpr) (*Provider, []error) {
+	// Assumes that call.Fun is wire.Generic.
+
+	if len(call.Args) != 1 {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("call to Generic takes exactly one argument"))}
+	}
+	fn, _ := instantiatedFunc(info, astutil.Unparen(call.Args[0]))
+	if fn == nil {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("argument to Generic must be an instantiation of a generic provider function"))}
+	}
+	item, errs := oc.get(fn)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	p := item.(*Provider)
+	if err := verifyInferable(oc.fset, call.Pos(), p); err != nil {
+		return nil, []error{err}
+	}
+	return p, nil
 }
 
-// funcOutput validates an injector or provider function's return signature.
-func funcOutput(sig *types.Signature) (outputSignature, error) {
-	results := sig.Results()
-	switch results.Len() {
-	case 0:
-		return outputSignature{}, errors.New("no return values")
-	case 1:
-		return outputSignature{out: results.At(0).Type()}, nil
-	case 2:
-		out := results.At(0).Type()
-		switch t := results.At(1).Type(); {
-		case types.Identical(t, errorType):
-			return outputSignature{out: out, err: true}, nil
-		case types.Identical(t, cleanupType):
-			return outputSignature{out: out, cleanup: true}, nil
-		default:
-			return outputSignature{}, fmt.Errorf("second return type is %s; must be error or func()", types.TypeString(t, nil))
-		}
-	case 3:
-		if t := results.At(1).Type(); !types.Identical(t, cleanupType) {
-			return outputSignature{}, fmt.Errorf("second return type is %s; must be func()", types.TypeString(t, nil))
-		}
-		if t := results.At(2).Type(); !types.Identical(t, errorType) {
-			return outputSignature{}, fmt.Errorf("third return type is %s; must be error", types.TypeString(t, nil))
+// verifyInferable ensures that every type parameter of the generic provider
+// p can be inferred from its output type. Otherwise, p can only be used if
+// it is instantiated explicitly, and the error is reported at pos.
+func verifyInferable(fset *token.FileSet, pos token.Pos, p *Provider) error {
+	for i := 0; i < p.TypeParams.Len(); i++ {
+		tp := p.TypeParams.At(i)
+		if !mentionsTypeParam(p.Out[0], tp) {
+			return notePosition(fset.Position(pos), fmt.Errorf("cannot infer type parameter %s of generic provider %s from its output type %s; instantiate it explicitly", tp, p.Name, types.TypeString(p.Out[0], nil)))
 		}
-		return outputSignature{
-			out:     results.At(0).Type(),
-			cleanup: true,
-			err:     true,
-		}, nil
-	default:
-		return outputSignature{}, errors.New("too many return values")
 	}
+	return nil
 }
 
-// processStructLiteralProvider creates a provider for a named struct type.
-// It produces pointer and non-pointer variants via two values in Out.
-//
-// This is a copy of the old processStructProvider, which is deprecated now.
-// It will not support any new feature introduced after v0.2. Please use the new
-// wire.Struct syntax for those.
-func processStructLiteralProvider(fset *token.FileSet, typeName *types.TypeName) (*Provider, []error) {
-	out := typeName.Type()
-	st, ok := out.Underlying().(*types.Struct)
+// processInto creates a contribution to a slice or map type from a call to
+// wire.Into or wire.IntoMap, as given by fnName.
+func (oc *objectCache) processInto(info *types.Info, pkgPath string, call *ast.CallExpr, fnName string) (*Contribution, []error) {
+	// Assumes that call.Fun is wire.Into or wire.IntoMap.
+
+	fset := oc.fset
+	nargs, kind := 2, "slice"
+	if fnName == "IntoMap" {
+		nargs, kind = 3, "map"
+	}
+	if len(call.Args) != nargs {
+		return nil, []error{notePosition(fset.Position(call.Pos()), fmt.Errorf("call to %s takes exactly %d arguments", fnName, nargs))}
+	}
+	collType := info.TypeOf(call.Args[0])
+	collPtr, ok := collType.(*types.Pointer)
 	if !ok {
-		return nil, []error{fmt.Errorf("%v does not name a struct", typeName)}
+		return nil, []error{notePosition(fset.Position(call.Pos()), fmt.Errorf("first argument to %s must be a pointer to a %s type; found %s", fnName, kind, types.TypeString(collType, nil)))}
 	}
-
-	pos := typeName.Pos()
-	fmt.Fprintf(os.Stderr,
-		"Warning: %v, see https://godoc.org/github.com/google/wire#Struct for more information.\n",
-		notePosition(fset.Position(pos),
-			fmt.Errorf("using struct literal to inject %s is deprecated and will be removed in the next release; use wire.Struct instead",
-				typeName.Type())))
-	provider := &Provider{
-		Pkg:      typeName.Pkg(),
-		Name:     typeName.Name(),
-		Pos:      pos,
-		Args:     make([]ProviderInput, st.NumFields()),
-		IsStruct: true,
-		Out:      []types.Type{out, types.NewPointer(out)},
+	c := &Contribution{
+		Pos:        call.Pos(),
+		Collection: collPtr.Elem(),
 	}
-	for i := 0; i < st.NumFields(); i++ {
-		f := st.Field(i)
-		provider.Args[i] = ProviderInput{
-			Type:      f.Type(),
-			FieldName: f.Name(),
+	var elem types.Type
+	switch t := collPtr.Elem().Underlying().(type) {
+	case *types.Slice:
+		if fnName == "Into" {
+			elem = t.Elem()
 		}
-		for j := 0; j < i; j++ {
-			if types.Identical(provider.Args[i].Type, provider.Args[j].Type) {
-				return nil, []error{notePosition(fset.Position(pos), fmt.Errorf("provider struct has multiple fields of type %s", types.TypeString(provider.Args[j].Type, nil)))}
+	case *types.Map:
+		if fnName == "IntoMap" {
+			elem = t.Elem()
+			c.key, c.keyInfo = call.Args[1], info
+			if tv := info.Types[c.key]; tv.Value == nil {
+				return nil, []error{notePosition(fset.Position(call.Pos()), errors.New("map key passed to IntoMap must be a constant"))}
 			}
 		}
 	}
-	return provider, nil
-}
-
-// processStructProvider creates a provider for a named struct type.
+	if elem == nil {
+		return nil, []error{notePosition(fset.Position(call.Pos()), fmt.Errorf("first argument to %s must be a pointer to a %s type; found %s", fnName, kind, types.TypeString(collType, nil)))}
+	}
+	item, errs := oc.processExpr(info, pkgPath, call.Args[nargs-1], "")
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	var outs []types.Type
+	switch item := item.(type) {
+	case *Provider:
+		if item.TypeParams != nil {
+			return nil, []error{notePosition(fset.Position(call.Pos()), fmt.Errorf("last argument to %s may not be a call to Generic; instantiate the provider explicitly", fnName))}
+		}
+		c.Provider, outs = item, item.Out
+	case *Value:
+		c.Value, outs = item, []types.Type{item.Out}
+	default:
+		return nil, []error{notePosition(fset.Position(call.Pos()), fmt.Errorf("last argument to %s must be a provider function, a struct provider or a value", fnName))}
+	}
+	// Prefer an output of exactly the element type, as for a struct
+	// provider, which provides both S and *S.
+	for _, out := range outs {
+		if types.Identical(out, elem) {
+			c.Out = out
+		}
+	}
+	for _, out := range outs {
+		if c.Out == nil && types.AssignableTo(out, elem) {
+			c.Out = out
+		}
+	}
+	if c.Out == nil {
+		return nil, []error{notePosition(fset.Position(call.Pos()), fmt.Errorf("%s cannot be added to %s", typeString(outs[0]), types.TypeString(c.Collection, nil)))}
+	}
+	return c, nil
+}
+
+// processOverride creates an override from a wire.Override call, or the
+// fields of a default from a wire.Default call.
+func (oc *objectCache) processOverride(info *types.Info, pkgPath string, call *ast.CallExpr, fnName string) (*Override, []error) {
+	// Assumes that call.Fun is wire.Override or wire.Default.
+
+	if len(call.Args) != 1 {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), fmt.Errorf("call to %s takes exactly one argument", fnName))}
+	}
+	item, errs := oc.processExpr(info, pkgPath, call.Args[0], "")
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	o := &Override{Pos: call.Pos()}
+	switch item := item.(type) {
+	case *Provider:
+		if item.TypeParams != nil {
+			return nil, []error{notePosition(oc.fset.Position(call.Pos()), fmt.Errorf("argument to %s may not be a call to Generic; instantiate the provider explicitly", fnName))}
+		}
+		o.Provider = item
+	case *Value:
+		o.Value = item
+	case *IfaceBinding:
+		o.Binding = item
+	default:
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), fmt.Errorf("argument to %s must be a provider function, a struct provider, a value or an interface binding", fnName))}
+	}
+	return o, nil
+}
+
+// processIntercept creates an intercept from a wire.Intercept call.
+// runtimePkg is the loaded wireruntime package, or nil if it isn't imported.
+func processIntercept(fset *token.FileSet, info *types.Info, pkgPath string, runtimePkg *packages.Package, call *ast.CallExpr) (*Intercept, error) {
+	// Assumes that call.Fun is wire.Intercept.
+
+	if len(call.Args) != 1 {
+		return nil, notePosition(fset.Position(call.Pos()), errors.New("call to Intercept takes exactly one argument"))
+	}
+	argType := info.TypeOf(call.Args[0])
+	ptr, ok := argType.(*types.Pointer)
+	if !ok {
+		return nil, notePosition(fset.Position(call.Pos()),
+			fmt.Errorf("argument to Intercept must be a pointer to an interface type; found %s", types.TypeString(argType, nil)))
+	}
+	iface := ptr.Elem()
+	methodSet, ok := iface.Underlying().(*types.Interface)
+	if !ok {
+		return nil, notePosition(fset.Position(call.Pos()),
+			fmt.Errorf("argument to Intercept must be a pointer to an interface type; found %s", types.TypeString(argType, nil)))
+	}
+	if methodSet.IsImplicit() || !methodSet.IsMethodSet() {
+		return nil, notePosition(fset.Position(call.Pos()),
+			fmt.Errorf("%s is a type constraint, which can't be intercepted", types.TypeString(iface, nil)))
+	}
+	for i := 0; i < methodSet.NumMethods(); i++ {
+		m := methodSet.Method(i)
+		if !m.Exported() && m.Pkg().Path() != pkgPath {
+			return nil, notePosition(fset.Position(call.Pos()),
+				fmt.Errorf("%s has unexported method %s, which can't be intercepted outside package %s", types.TypeString(iface, nil), m.Name(), m.Pkg().Path()))
+		}
+	}
+	if runtimePkg == nil {
+		return nil, notePosition(fset.Position(call.Pos()),
+			errors.New("wire.Intercept needs a provider of wireruntime.Interceptor, but github.com/google/wire/wireruntime is not imported"))
+	}
+	interceptor, ok := runtimePkg.Types.Scope().Lookup("Interceptor").(*types.TypeName)
+	if !ok {
+		return nil, notePosition(fset.Position(call.Pos()), fmt.Errorf("package %s does not declare Interceptor", runtimePkg.PkgPath))
+	}
+	return &Intercept{Pos: call.Pos(), Iface: iface, Interceptor: interceptor.Type()}, nil
+}
+
+// processDecorate creates a decorator from a wire.Decorate call.
+func (oc *objectCache) processDecorate(info *types.Info, pkgPath string, call *ast.CallExpr) (*Decorator, []error) {
+	// Assumes that call.Fun is wire.Decorate.
+
+	if len(call.Args) != 1 {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("call to Decorate takes exactly one argument"))}
+	}
+	item, errs := oc.processExpr(info, pkgPath, call.Args[0], "")
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	p, ok := item.(*Provider)
+	if !ok || p.IsStruct || p.Factory != nil || p.Select != nil {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("argument to Decorate must be a provider function"))}
+	}
+	if p.TypeParams != nil {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("argument to Decorate may not be a call to Generic; instantiate the provider explicitly"))}
+	}
+	if len(p.Args) == 0 || !types.Identical(p.Args[0].Type, p.Out[0]) {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), fmt.Errorf("decorator %s must take the type it provides, %s, as its first argument", p.Name, types.TypeString(p.Out[0], nil)))}
+	}
+	return &Decorator{Pos: call.Pos(), Provider: p}, nil
+}
+
+// processPostConstruct creates a post-construct method from a
+// wire.PostConstruct call.
+func processPostConstruct(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*PostConstruct, error) {
+	// Assumes that call.Fun is wire.PostConstruct.
+
+	if len(call.Args) != 1 {
+		return nil, notePosition(fset.Position(call.Pos()), errors.New("call to PostConstruct takes exactly one argument"))
+	}
+	argType := info.TypeOf(call.Args[0])
+	ptr, ok := argType.(*types.Pointer)
+	if !ok {
+		return nil, notePosition(fset.Position(call.Pos()),
+			fmt.Errorf("argument to PostConstruct must be a pointer; found %s", types.TypeString(argType, nil)))
+	}
+	typ := ptr.Elem()
+	var methods []string
+	for _, name := range []string{"Validate", "Init"} {
+		if hasErrorMethod(typ, name) {
+			methods = append(methods, name)
+		}
+	}
+	switch len(methods) {
+	case 0:
+		return nil, notePosition(fset.Position(call.Pos()),
+			fmt.Errorf("%s has no Validate() error or Init() error method", types.TypeString(typ, nil)))
+	case 2:
+		return nil, notePosition(fset.Position(call.Pos()),
+			fmt.Errorf("%s has both Validate() error and Init() error methods", types.TypeString(typ, nil)))
+	}
+	return &PostConstruct{Pos: call.Pos(), Type: typ, Method: methods[0]}, nil
+}
+
+// processOptional creates an optional type from a wire.Optional call.
+func processOptional(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*Optional, error) {
+	// Assumes that call.Fun is wire.Optional.
+
+	if len(call.Args) != 1 {
+		return nil, notePosition(fset.Position(call.Pos()), errors.New("call to Optional takes exactly one argument"))
+	}
+	argType := info.TypeOf(call.Args[0])
+	ptr, ok := argType.(*types.Pointer)
+	if !ok {
+		return nil, notePosition(fset.Position(call.Pos()),
+			fmt.Errorf("argument to Optional must be a pointer; found %s", types.TypeString(argType, nil)))
+	}
+	return &Optional{Pos: call.Pos(), Type: ptr.Elem()}, nil
+}
+
+// hasErrorMethod reports whether a variable of type t has a method with
+// the given name that takes no arguments and returns an error.
+func hasErrorMethod(t types.Type, name string) bool {
+	iface := types.NewInterfaceType([]*types.Func{types.NewFunc(token.NoPos, nil, name, cleanupErrType)}, nil).Complete()
+	return types.Implements(t, iface) || types.Implements(types.NewPointer(t), iface)
+}
+
+// processAutoClose creates a provider from a wire.AutoClose call, whose
+// cleanup function is the Close method of the provided value.
+func (oc *objectCache) processAutoClose(info *types.Info, pkgPath string, call *ast.CallExpr) (*Provider, []error) {
+	// Assumes that call.Fun is wire.AutoClose.
+
+	if len(call.Args) != 1 {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("call to AutoClose takes exactly one argument"))}
+	}
+	item, errs := oc.processExpr(info, pkgPath, call.Args[0], "")
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	p, ok := item.(*Provider)
+	if !ok || p.IsStruct || p.Factory != nil || p.Select != nil {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("argument to AutoClose must be a provider function"))}
+	}
+	if p.TypeParams != nil {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("argument to AutoClose may not be a call to Generic; instantiate the provider explicitly"))}
+	}
+	if p.HasCleanup {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), fmt.Errorf("provider %s passed to AutoClose already returns a cleanup function", p.Name))}
+	}
+	out, _ := unqualify(p.Out[0])
+	if !types.Implements(out, closerType) {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), fmt.Errorf("%s provided by %s has no Close() error method", types.TypeString(out, nil), p.Name))}
+	}
+	ac := *p
+	ac.HasCleanup = true
+	ac.Cleanup = CleanupFuncErr
+	ac.AutoClose = true
+	return &ac, nil
+}
+
+// processFactory creates a provider of a factory function from a
+// wire.Factory call.
+func (oc *objectCache) processFactory(info *types.Info, pkgPath string, call *ast.CallExpr) (*Provider, []error) {
+	// Assumes that call.Fun is wire.Factory.
+
+	if len(call.Args) != 2 {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("call to Factory takes exactly two arguments"))}
+	}
+	argType := info.TypeOf(call.Args[0])
+	ptr, ok := argType.(*types.Pointer)
+	if !ok {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()),
+			fmt.Errorf("first argument to Factory must be a pointer to a function type; found %s", types.TypeString(argType, nil)))}
+	}
+	fnType := ptr.Elem()
+	sig, ok := fnType.Underlying().(*types.Signature)
+	if !ok {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()),
+			fmt.Errorf("first argument to Factory must be a pointer to a function type; found %s", types.TypeString(argType, nil)))}
+	}
+	if sig.Variadic() {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), fmt.Errorf("factory function type %s may not be variadic", types.TypeString(fnType, nil)))}
+	}
+	item, errs := oc.processExpr(info, pkgPath, call.Args[1], "")
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	p, ok := item.(*Provider)
+	if !ok || p.IsStruct || p.Factory != nil || p.Select != nil {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("second argument to Factory must be a provider function"))}
+	}
+	if p.TypeParams != nil {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("second argument to Factory may not be a call to Generic; instantiate the provider explicitly"))}
+	}
+	fnOut, err := funcOutput(sig)
+	if err != nil {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), fmt.Errorf("factory function type %s: %v", types.TypeString(fnType, nil), err))}
+	}
+	switch {
+	case !types.Identical(fnOut.out, p.Out[0]):
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()),
+			fmt.Errorf("factory function type %s returns %s, but %s provides %s", types.TypeString(fnType, nil), types.TypeString(fnOut.out, nil), p.Name, types.TypeString(p.Out[0], nil)))}
+	case p.HasCleanup && fnOut.cleanup == NoCleanup:
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()),
+			fmt.Errorf("%s returns a cleanup function, but factory function type %s does not", p.Name, types.TypeString(fnType, nil)))}
+	case fnOut.cleanup != p.Cleanup:
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()),
+			fmt.Errorf("factory function type %s returns a cleanup function of type %v, but %s returns %v", types.TypeString(fnType, nil), fnOut.cleanup, p.Name, p.Cleanup))}
+	case p.HasErr && !fnOut.err:
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()),
+			fmt.Errorf("%s returns an error, but factory function type %s does not", p.Name, types.TypeString(fnType, nil)))}
+	}
+	fa := &Factory{Provider: p, Sig: sig, Params: make([]int, len(p.Args))}
+	for i := range fa.Params {
+		fa.Params[i] = -1
+	}
+	for i := 0; i < sig.Params().Len(); i++ {
+		t := sig.Params().At(i).Type()
+		found := false
+		for j, arg := range p.Args {
+			if types.Identical(t, arg.Type) {
+				if fa.Params[j] != -1 {
+					return nil, []error{notePosition(oc.fset.Position(call.Pos()),
+						fmt.Errorf("factory function type %s has multiple parameters of type %s", types.TypeString(fnType, nil), types.TypeString(t, nil)))}
+				}
+				fa.Params[j] = i
+				found = true
+			}
+		}
+		if !found {
+			return nil, []error{notePosition(oc.fset.Position(call.Pos()),
+				fmt.Errorf("parameter %d of factory function type %s has type %s, which %s does not take", i, types.TypeString(fnType, nil), types.TypeString(t, nil), p.Name))}
+		}
+	}
+	var args []ProviderInput
+	for i, arg := range p.Args {
+		if fa.Params[i] == -1 {
+			args = append(args, arg)
+		}
+	}
+	return &Provider{
+		Pkg:     p.Pkg,
+		Name:    p.Name,
+		Pos:     call.Pos(),
+		Args:    args,
+		Out:     []types.Type{fnType},
+		Factory: fa,
+	}, nil
+}
+
+// processSelect creates a provider that chooses between provider sets from a
+// wire.Select call. The branch sets are solved up front, with the types that
+// they don't provide as their givens.
+func (oc *objectCache) processSelect(info *types.Info, pkgPath string, call *ast.CallExpr) (*Provider, []error) {
+	// Assumes that call.Fun is wire.Select.
+
+	if len(call.Args) != 3 {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("call to Select takes exactly three arguments"))}
+	}
+	argType := info.TypeOf(call.Args[0])
+	ptr, ok := argType.(*types.Pointer)
+	if !ok {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()),
+			fmt.Errorf("first argument to Select must be a pointer; found %s", types.TypeString(argType, nil)))}
+	}
+	out := ptr.Elem()
+	item, errs := oc.processExpr(info, pkgPath, call.Args[1], "")
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	key, ok := item.(*Provider)
+	if !ok || key.IsStruct || key.Factory != nil || key.Select != nil {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("second argument to Select must be a provider function"))}
+	}
+	if key.TypeParams != nil {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("second argument to Select may not be a call to Generic; instantiate the provider explicitly"))}
+	}
+	if key.HasCleanup || key.HasErr {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), fmt.Errorf("key function %s passed to Select may not return a cleanup function or an error", key.Name))}
+	}
+	lit, ok := astutil.Unparen(call.Args[2]).(*ast.CompositeLit)
+	m, _ := info.TypeOf(call.Args[2]).Underlying().(*types.Map)
+	if !ok || m == nil || !isProviderSetType(m.Elem()) {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("third argument to Select must be a map literal of provider sets"))}
+	}
+	if !types.Identical(m.Key(), key.Out[0]) {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()),
+			fmt.Errorf("key function %s returns %s, but the keys of the branches are %s", key.Name, types.TypeString(key.Out[0], nil), types.TypeString(m.Key(), nil)))}
+	}
+	if len(lit.Elts) == 0 {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("call to Select has no branches"))}
+	}
+	sel := &Select{Key: key, keyInfo: info}
+	ec := new(errorCollector)
+	for _, elt := range lit.Elts {
+		kv := elt.(*ast.KeyValueExpr)
+		if tv := info.Types[kv.Key]; tv.Value == nil {
+			ec.add(notePosition(oc.fset.Position(kv.Pos()), errors.New("branch key passed to Select must be a constant")))
+			continue
+		}
+		item, errs := oc.processExpr(info, pkgPath, kv.Value, "")
+		if len(errs) > 0 {
+			ec.add(errs...)
+			continue
+		}
+		sel.keys = append(sel.keys, kv.Key)
+		sel.Branches = append(sel.Branches, item.(*ProviderSet))
+	}
+	if len(ec.errors) > 0 {
+		return nil, ec.errors
+	}
+	// Collect the types that the branches need from the enclosing set.
+	empty := types.NewTuple()
+	for i, b := range sel.Branches {
+		var free []types.Type
+		_, _, _, errs := solveGraph(oc.fset, out, nil, empty, b, &free)
+		for _, t := range free {
+			if types.Identical(t, out) {
+				errs = append(errs, fmt.Errorf("branch %s of Select does not provide %s", types.ExprString(sel.keys[i]), types.TypeString(out, nil)))
+				continue
+			}
+			found := false
+			for _, f := range sel.Free {
+				if types.Identical(f, t) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				sel.Free = append(sel.Free, t)
+			}
+		}
+		ec.add(notePositionAll(oc.fset.Position(sel.keys[i].Pos()), errs)...)
+	}
+	if len(ec.errors) > 0 {
+		return nil, ec.errors
+	}
+	vars := make([]*types.Var, len(sel.Free))
+	for i, t := range sel.Free {
+		vars[i] = types.NewParam(token.NoPos, nil, "", t)
+	}
+	given := types.NewTuple(vars...)
+	for _, b := range sel.Branches {
+		calls, outIndex, _, errs := solveGraph(oc.fset, out, nil, given, b, nil)
+		ec.add(errs...)
+		sel.calls = append(sel.calls, calls)
+		sel.outs = append(sel.outs, outIndex)
+	}
+	if len(ec.errors) > 0 {
+		return nil, ec.errors
+	}
+	args := append([]ProviderInput(nil), key.Args...)
+	for _, t := range sel.Free {
+		i := 0
+		for i < len(args) && !types.Identical(args[i].Type, t) {
+			i++
+		}
+		if i == len(args) {
+			args = append(args, ProviderInput{Type: t})
+		}
+		sel.freeArgs = append(sel.freeArgs, i)
+	}
+	return &Provider{
+		Pkg:    key.Pkg,
+		Name:   key.Name,
+		Pos:    call.Pos(),
+		Args:   args,
+		Out:    []types.Type{out},
+		Select: sel,
+	}, nil
+}
+
+// processInjectorOption creates an injector option from a call to one of the
+// option markers, like wire.RecoverPanics.
+func processInjectorOption(call *ast.CallExpr, name string) (*injectorOption, error) {
+	if len(call.Args) != 0 {
+		return nil, fmt.Errorf("call to %s takes no arguments", name)
+	}
+	opt := &injectorOption{name: name}
+	switch name {
+	case "RecoverPanics":
+		opt.set = func(opts *InjectorOptions) { opts.RecoverPanics = true }
+	case "PanicsAsErrors":
+		opt.set = func(opts *InjectorOptions) { opts.PanicsAsErrors = true }
+	case "Parallel":
+		opt.set = func(opts *InjectorOptions) { opts.Parallel = true }
+	case "CheckContext":
+		opt.set = func(opts *InjectorOptions) { opts.CheckContext = true }
+	case "WrapErrors":
+		opt.set = func(opts *InjectorOptions) { opts.WrapErrors = true }
+	default:
+		panic("unknown injector option " + name)
+	}
+	return opt, nil
+}
+
+// processNamed creates a provider or value whose outputs are qualified by a
+// name from a wire.Named call.
+func (oc *objectCache) processNamed(info *types.Info, pkgPath string, call *ast.CallExpr) (interface{}, []error) {
+	// Assumes that call.Fun is wire.Named.
+
+	if len(call.Args) != 2 {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("call to Named takes exactly two arguments"))}
+	}
+	name, err := nameArg(info, call.Args[0])
+	if err != nil {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), fmt.Errorf("first argument to Named: %v", err))}
+	}
+	item, errs := oc.processExpr(info, pkgPath, call.Args[1], "")
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	switch item := item.(type) {
+	case *Provider:
+		if item.TypeParams != nil {
+			return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("second argument to Named may not be a call to Generic; instantiate the provider explicitly"))}
+		}
+		named := *item
+		named.Out = make([]types.Type, len(item.Out))
+		for i, t := range item.Out {
+			named.Out[i] = oc.qualifiedType(t, name)
+		}
+		return &named, nil
+	case *Value:
+		named := *item
+		named.Out = oc.qualifiedType(item.Out, name)
+		return &named, nil
+	default:
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("second argument to Named must be a provider function, a struct provider or a value"))}
+	}
+}
+
+// processNamedParams creates a provider from a wire.NamedParams call whose
+// parameters request values qualified by the given names.
+func (oc *objectCache) processNamedParams(info *types.Info, call *ast.CallExpr) (*Provider, []error) {
+	// Assumes that call.Fun is wire.NamedParams.
+
+	if len(call.Args) < 1 {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("call to NamedParams must specify a provider function"))}
+	}
+	fnExpr := astutil.Unparen(call.Args[0])
+	fn, targs := instantiatedFunc(info, fnExpr)
+	if fn == nil {
+		fn, _ = qualifiedIdentObject(info, fnExpr).(*types.Func)
+	}
+	if fn == nil {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), errors.New("first argument to NamedParams must be a provider function"))}
+	}
+	sig := fn.Type().(*types.Signature)
+	if targs != nil {
+		inst, err := types.Instantiate(nil, sig, targs, true)
+		if err != nil {
+			return nil, []error{notePosition(oc.fset.Position(call.Pos()), fmt.Errorf("wrong signature for provider %s: %v", fn.Name(), err))}
+		}
+		sig = inst.(*types.Signature)
+	}
+	if n := sig.Params().Len(); len(call.Args)-1 != n {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), fmt.Errorf("call to NamedParams must give a name for each of the %d parameters of %s; use \"\" for unnamed parameters", n, fn.Name()))}
+	}
+	provider, err := newFuncProvider(fn.Pkg(), fn.Name(), fn.Pos(), sig)
+	if err != nil {
+		return nil, []error{notePosition(oc.fset.Position(fn.Pos()), fmt.Errorf("wrong signature for provider %s: %v", fn.Name(), err))}
+	}
+	provider.TypeArgs = targs
+	ec := new(errorCollector)
+	for i, arg := range call.Args[1:] {
+		name, err := nameArg(info, arg)
+		if err == errUnnamed {
+			continue
+		}
+		if err != nil {
+			ec.add(notePosition(oc.fset.Position(arg.Pos()), fmt.Errorf("argument to NamedParams: %v", err)))
+			continue
+		}
+		provider.Args[i].Type = oc.qualifiedType(provider.Args[i].Type, name)
+	}
+	if len(ec.errors) > 0 {
+		return nil, ec.errors
+	}
+	if err := verifyDistinctArgs(provider); err != nil {
+		return nil, []error{notePosition(oc.fset.Position(call.Pos()), err)}
+	}
+	return provider, nil
+}
+
+// errUnnamed is returned by nameArg for the empty name.
+var errUnnamed = errors.New("name must not be empty")
+
+// nameArg returns the name given by a constant string argument to
+// wire.Named or wire.NamedParams.
+func nameArg(info *types.Info, arg ast.Expr) (string, error) {
+	tv := info.Types[arg]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", errors.New("name must be a constant string")
+	}
+	name := constant.StringVal(tv.Value)
+	if name == "" {
+		return "", errUnnamed
+	}
+	if !token.IsIdentifier(name) {
+		return "", fmt.Errorf("invalid name %q; names must be Go identifiers", name)
+	}
+	return name, nil
+}
+
+// qualifierPkg is the package of the generic types that represent names
+// given to wire.Named. It is never seen by the type checker.
+var qualifierPkg = types.NewPackage("github.com/google/wire#qualifier", "qualifier")
+
+// qualifiedType returns the type that stands for t qualified by name.
+//
+// Wire keys providers by type, so a qualified type is represented by the
+// instantiation name[t] of a generic type that only exists inside Wire. Two
+// qualified types are identical if and only if they have the same name and
+// identical base types, so qualified types can be used wherever Wire uses
+// types as keys.
+func (oc *objectCache) qualifiedType(t types.Type, name string) types.Type {
+	q := oc.qualifiers[name]
+	if q == nil {
+		tparam := types.NewTypeParam(types.NewTypeName(token.NoPos, qualifierPkg, "T", nil), types.NewInterfaceType(nil, nil))
+		q = types.NewNamed(types.NewTypeName(token.NoPos, qualifierPkg, name, nil), types.NewStruct(nil, nil), nil)
+		q.SetTypeParams([]*types.TypeParam{tparam})
+		oc.qualifiers[name] = q
+	}
+	inst, err := types.Instantiate(nil, q, []types.Type{t}, false)
+	if err != nil {
+		panic(err)
+	}
+	return inst
+}
+
+// unqualify returns the base type and the name of the qualified type t, or
+// t itself and the empty string if t is not qualified.
+func unqualify(t types.Type) (types.Type, string) {
+	if n, ok := t.(*types.Named); ok && n.Obj().Pkg() == qualifierPkg {
+		return n.TypeArgs().At(0), n.Obj().Name()
+	}
+	return t, ""
+}
+
+// TypeString is like types.TypeString(t, nil), but formats the types of
+// named providers like `*DB named "primary"`.
+func TypeString(t types.Type) string {
+	return typeString(t)
+}
+
+// typeString is like types.TypeString(t, nil), but also formats qualified
+// types.
+func typeString(t types.Type) string {
+	if base, name := unqualify(t); name != "" {
+		return fmt.Sprintf("%s named %q", types.TypeString(base, nil), name)
+	}
+	return types.TypeString(t, nil)
+}
+
+func injectorFuncSignature(sig *types.Signature) (*types.Tuple, outputSignature, error) {
+	out, err := funcOutput(sig)
+	if err != nil {
+		return nil, outputSignature{}, err
+	}
+	return sig.Params(), out, nil
+}
+
+type outputSignature struct {
+	out     types.Type
+	cleanup CleanupKind
+	err     bool
+}
+
+// CleanupKind is the signature of a cleanup function returned by a provider
+// or injector.
+type CleanupKind int
+
+const (
+	// NoCleanup means that no cleanup function is returned.
+	NoCleanup CleanupKind = iota
+	// CleanupFunc is a cleanup function of type func().
+	CleanupFunc
+	// CleanupFuncErr is a cleanup function of type func() error.
+	CleanupFuncErr
+	// CleanupFuncCtxErr is a cleanup function of type
+	// func(context.Context) error.
+	CleanupFuncCtxErr
+)
+
+// String returns the Go type of the cleanup function.
+func (k CleanupKind) String() string {
+	switch k {
+	case CleanupFunc:
+		return "func()"
+	case CleanupFuncErr:
+		return "func() error"
+	case CleanupFuncCtxErr:
+		return "func(context.Context) error"
+	default:
+		return "no cleanup"
+	}
+}
+
+// cleanupKindOf returns the kind of cleanup function of type t, or
+// NoCleanup if t is not a cleanup function type.
+func cleanupKindOf(t types.Type) CleanupKind {
+	switch {
+	case types.Identical(t, cleanupType):
+		return CleanupFunc
+	case types.Identical(t, cleanupErrType):
+		return CleanupFuncErr
+	}
+	sig, ok := t.(*types.Signature)
+	if !ok || sig.Variadic() || sig.Params().Len() != 1 || sig.Results().Len() != 1 {
+		return NoCleanup
+	}
+	if isContextType(sig.Params().At(0).Type()) && types.Identical(sig.Results().At(0).Type(), errorType) {
+		return CleanupFuncCtxErr
+	}
+	return NoCleanup
+}
+
+// isContextType reports whether t is context.Context.
+func isContextType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
+
+// funcOutput validates an injector or provider function's return signature.
+func funcOutput(sig *types.Signature) (outputSignature, error) {
+	results := sig.Results()
+	switch results.Len() {
+	case 0:
+		return outputSignature{}, errors.New("no return values")
+	case 1:
+		return outputSignature{out: results.At(0).Type()}, nil
+	case 2:
+		out := results.At(0).Type()
+		t := results.At(1).Type()
+		if types.Identical(t, errorType) {
+			return outputSignature{out: out, err: true}, nil
+		}
+		if k := cleanupKindOf(t); k != NoCleanup {
+			return outputSignature{out: out, cleanup: k}, nil
+		}
+		return outputSignature{}, fmt.Errorf("second return type is %s; must be error or a cleanup function", types.TypeString(t, nil))
+	case 3:
+		k := cleanupKindOf(results.At(1).Type())
+		if k == NoCleanup {
+			return outputSignature{}, fmt.Errorf("second return type is %s; must be func(), func() error or func(context.Context) error", types.TypeString(results.At(1).Type(), nil))
+		}
+		if t := results.At(2).Type(); !types.Identical(t, errorType) {
+			return outputSignature{}, fmt.Errorf("third return type is %s; must be error", types.TypeString(t, nil))
+		}
+		return outputSignature{
+			out:     results.At(0).Type(),
+			cleanup: k,
+			err:     true,
+		}, nil
+	default:
+		return outputSignature{}, errors.New("too many return values")
+	}
+}
+
+// processStructLiteralProvider creates a provider for a named struct type.
+// It produces pointer and non-pointer variants via two values in Out.
+//
+// This is a copy of the old processStructProvider, which is deprecated now.
+// It will not support any new feature introduced after v0.2. Please use the new
+// wire.Struct syntax for those.
+func processStructLiteralProvider(fset *token.FileSet, typeName *types.TypeName) (*Provider, []error) {
+	out := typeName.Type()
+	st, ok := out.Underlying().(*types.Struct)
+	if !ok {
+		return nil, []error{fmt.Errorf("%v does not name a struct", typeName)}
+	}
+
+	pos := typeName.Pos()
+	fmt.Fprintf(os.Stderr,
+		"Warning: %v, see https://godoc.org/github.com/google/wire#Struct for more information.\n",
+		notePosition(fset.Position(pos),
+			fmt.Errorf("using struct literal to inject %s is deprecated and will be removed in the next release; use wire.Struct instead",
+				typeName.Type())))
+	provider := &Provider{
+		Pkg:      typeName.Pkg(),
+		Name:     typeName.Name(),
+		Pos:      pos,
+		Args:     make([]ProviderInput, st.NumFields()),
+		IsStruct: true,
+		Out:      []types.Type{out, types.NewPointer(out)},
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		provider.Args[i] = ProviderInput{
+			Type:      f.Type(),
+			FieldName: f.Name(),
+		}
+		for j := 0; j < i; j++ {
+			if types.Identical(provider.Args[i].Type, provider.Args[j].Type) {
+				return nil, []error{notePosition(fset.Position(pos), fmt.Errorf("provider struct has multiple fields of type %s", types.TypeString(provider.Args[j].Type, nil)))}
+			}
+		}
+	}
+	return provider, nil
+}
+
+// processStructProvider creates a provider for a named struct type.
 // It produces pointer and non-pointer variants via two values in Out.
-func processStructProvider(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*Provider, error) {
+func (oc *objectCache) processStructProvider(info *types.Info, call *ast.CallExpr) (*Provider, error) {
 	// Assumes that call.Fun is wire.Struct.
 
+	fset := oc.fset
 	if len(call.Args) < 1 {
 		return nil, notePosition(fset.Position(call.Pos()),
 			errors.New("call to Struct must specify the struct to be injected"))
@@ -811,42 +2161,90 @@ func processStructProvider(fset *token.FileSet, info *types.Info, call *ast.Call
 		return nil, notePosition(fset.Position(call.Pos()),
 			fmt.Errorf(firstArgReqFormat, types.TypeString(structType, nil)))
 	}
+	if _, ok := structPtr.Elem().Underlying().(*types.Struct); !ok {
+		return nil, notePosition(fset.Position(call.Pos()),
+			fmt.Errorf(firstArgReqFormat, types.TypeString(structPtr, nil)))
+	}
+	stExpr := call.Args[0].(*ast.CallExpr)
+	typeName := qualifiedIdentObject(info, instanceBase(stExpr.Args[0])) // should be either an identifier or selector
+	if typeName == nil {
+		return nil, notePosition(fset.Position(call.Pos()),
+			fmt.Errorf(firstArgReqFormat, types.TypeString(structPtr, nil)))
+	}
+	return oc.newStructProvider(call.Pos(), typeName, structPtr.Elem(), call.Args[1:])
+}
+
+// processStructOf creates a provider for a named struct type from a
+// wire.StructOf call.
+func (oc *objectCache) processStructOf(info *types.Info, call *ast.CallExpr) (*Provider, error) {
+	// Assumes that call.Fun is an instantiation of wire.StructOf.
 
-	st, ok := structPtr.Elem().Underlying().(*types.Struct)
+	fset := oc.fset
+	const typeArgReqFormat = "type argument to StructOf must be a named struct; found %s"
+	idx, ok := call.Fun.(*ast.IndexExpr)
 	if !ok {
 		return nil, notePosition(fset.Position(call.Pos()),
-			fmt.Errorf(firstArgReqFormat, types.TypeString(structPtr, nil)))
+			errors.New("call to StructOf must specify the struct to be injected as a type argument"))
+	}
+	st := info.TypeOf(idx.Index)
+	if _, ok := st.Underlying().(*types.Struct); !ok {
+		return nil, notePosition(fset.Position(call.Pos()),
+			fmt.Errorf(typeArgReqFormat, types.TypeString(st, nil)))
 	}
+	typeName := qualifiedIdentObject(info, instanceBase(idx.Index))
+	if typeName == nil {
+		return nil, notePosition(fset.Position(call.Pos()),
+			fmt.Errorf(typeArgReqFormat, types.TypeString(st, nil)))
+	}
+	return oc.newStructProvider(call.Pos(), typeName, st, call.Args)
+}
 
-	stExpr := call.Args[0].(*ast.CallExpr)
-	typeName := qualifiedIdentObject(info, stExpr.Args[0]) // should be either an identifier or selector
+// newStructProvider creates a provider for the struct type st named by
+// typeName, filling in the fields named by fieldArgs. A field tagged with
+// `wire:"name=..."` is filled in with the value qualified by that name.
+func (oc *objectCache) newStructProvider(pos token.Pos, typeName types.Object, st types.Type, fieldArgs []ast.Expr) (*Provider, error) {
+	fset := oc.fset
+	fields := st.Underlying().(*types.Struct)
 	provider := &Provider{
 		Pkg:      typeName.Pkg(),
 		Name:     typeName.Name(),
 		Pos:      typeName.Pos(),
 		IsStruct: true,
-		Out:      []types.Type{structPtr.Elem(), structPtr},
+		Out:      []types.Type{st, types.NewPointer(st)},
 	}
-	if allFields(call) {
-		for i := 0; i < st.NumFields(); i++ {
-			if isPrevented(st.Tag(i)) {
+	if named, ok := st.(*types.Named); ok && named.TypeArgs().Len() > 0 {
+		for i := 0; i < named.TypeArgs().Len(); i++ {
+			provider.TypeArgs = append(provider.TypeArgs, named.TypeArgs().At(i))
+		}
+	}
+	if allFields(fieldArgs) {
+		for i := 0; i < fields.NumFields(); i++ {
+			if isPrevented(fields.Tag(i)) {
 				continue
 			}
-			f := st.Field(i)
+			f := fields.Field(i)
+			typ, err := oc.fieldType(f, fields.Tag(i))
+			if err != nil {
+				return nil, notePosition(fset.Position(pos), err)
+			}
 			provider.Args = append(provider.Args, ProviderInput{
-				Type:      f.Type(),
+				Type:      typ,
 				FieldName: f.Name(),
 			})
 		}
 	} else {
-		provider.Args = make([]ProviderInput, len(call.Args)-1)
-		for i := 1; i < len(call.Args); i++ {
-			v, err := checkField(call.Args[i], st)
+		provider.Args = make([]ProviderInput, len(fieldArgs))
+		for i, arg := range fieldArgs {
+			v, err := checkField(arg, fields)
+			if err != nil {
+				return nil, notePosition(fset.Position(pos), err)
+			}
+			typ, err := oc.fieldType(v, fieldTag(fields, v))
 			if err != nil {
-				return nil, notePosition(fset.Position(call.Pos()), err)
+				return nil, notePosition(fset.Position(pos), err)
 			}
-			provider.Args[i-1] = ProviderInput{
-				Type:      v.Type(),
+			provider.Args[i] = ProviderInput{
+				Type:      typ,
 				FieldName: v.Name(),
 			}
 		}
@@ -854,19 +2252,44 @@ func processStructProvider(fset *token.FileSet, info *types.Info, call *ast.Call
 	for i := 0; i < len(provider.Args); i++ {
 		for j := 0; j < i; j++ {
 			if types.Identical(provider.Args[i].Type, provider.Args[j].Type) {
-				f := st.Field(j)
-				return nil, notePosition(fset.Position(f.Pos()), fmt.Errorf("provider struct has multiple fields of type %s", types.TypeString(provider.Args[j].Type, nil)))
+				f := fields.Field(j)
+				return nil, notePosition(fset.Position(f.Pos()), fmt.Errorf("provider struct has multiple fields of type %s", typeString(provider.Args[j].Type)))
 			}
 		}
 	}
 	return provider, nil
 }
 
-func allFields(call *ast.CallExpr) bool {
-	if len(call.Args) != 2 {
+// fieldType returns the type that a struct provider requests for field f
+// with the given tag: the field's type, qualified by the name in a
+// `wire:"name=..."` tag if there is one.
+func (oc *objectCache) fieldType(f *types.Var, tag string) (types.Type, error) {
+	v := reflect.StructTag(tag).Get("wire")
+	name := strings.TrimPrefix(v, "name=")
+	if name == v {
+		return f.Type(), nil
+	}
+	if !token.IsIdentifier(name) {
+		return nil, fmt.Errorf("field %s requests invalid name %q; names must be Go identifiers", f.Name(), name)
+	}
+	return oc.qualifiedType(f.Type(), name), nil
+}
+
+// fieldTag returns the tag of field f of st.
+func fieldTag(st *types.Struct, f *types.Var) string {
+	for i := 0; i < st.NumFields(); i++ {
+		if st.Field(i) == f {
+			return st.Tag(i)
+		}
+	}
+	return ""
+}
+
+func allFields(fieldArgs []ast.Expr) bool {
+	if len(fieldArgs) != 1 {
 		return false
 	}
-	b, ok := call.Args[1].(*ast.BasicLit)
+	b, ok := fieldArgs[0].(*ast.BasicLit)
 	if !ok {
 		return false
 	}
@@ -874,8 +2297,6 @@ func allFields(call *ast.CallExpr) bool {
 }
 
 // isPrevented checks whether field i is prevented by tag "-".
-// Since this is the only tag used by wire, we can do string comparison
-// without using reflect.
 func isPrevented(tag string) bool {
 	return reflect.StructTag(tag).Get("wire") == "-"
 }
@@ -911,16 +2332,36 @@ func processBind(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*If
 		}
 		provided = providedPtr.Elem()
 	}
-	if types.Identical(iface, provided) {
+	return newIfaceBinding(fset, call.Pos(), iface, methodSet, provided)
+}
+
+// processBindTo creates an interface binding from a wire.BindTo call.
+func processBindTo(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*IfaceBinding, error) {
+	// Assumes that call.Fun is an instantiation of wire.BindTo.
+
+	targs := funcTypeArgs(info, call.Fun)
+	iface, provided := targs[0], targs[1]
+	methodSet, ok := iface.Underlying().(*types.Interface)
+	if !ok {
 		return nil, notePosition(fset.Position(call.Pos()),
+			fmt.Errorf("first type argument to BindTo must be an interface type; found %s", types.TypeString(iface, nil)))
+	}
+	return newIfaceBinding(fset, call.Pos(), iface, methodSet, provided)
+}
+
+// newIfaceBinding creates a binding of iface to provided, verifying that
+// provided implements iface.
+func newIfaceBinding(fset *token.FileSet, pos token.Pos, iface types.Type, methodSet *types.Interface, provided types.Type) (*IfaceBinding, error) {
+	if types.Identical(iface, provided) {
+		return nil, notePosition(fset.Position(pos),
 			errors.New("cannot bind interface to itself"))
 	}
 	if !types.Implements(provided, methodSet) {
-		return nil, notePosition(fset.Position(call.Pos()),
+		return nil, notePosition(fset.Position(pos),
 			fmt.Errorf("%s does not implement %s", types.TypeString(provided, nil), types.TypeString(iface, nil)))
 	}
 	return &IfaceBinding{
-		Pos:      call.Pos(),
+		Pos:      pos,
 		Iface:    iface,
 		Provided: provided,
 	}, nil
@@ -933,10 +2374,46 @@ func processValue(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*V
 	if len(call.Args) != 1 {
 		return nil, notePosition(fset.Position(call.Pos()), errors.New("call to Value takes exactly one argument"))
 	}
+	if !isSimpleValueExpr(info, call.Args[0]) {
+		return nil, notePosition(fset.Position(call.Pos()), errors.New("argument to Value is too complex"))
+	}
+	// Result type can't be an interface type; use wire.InterfaceValue for that.
+	argType := info.TypeOf(call.Args[0])
+	if _, isInterfaceType := argType.Underlying().(*types.Interface); isInterfaceType {
+		return nil, notePosition(fset.Position(call.Pos()), fmt.Errorf("argument to Value may not be an interface value (found %s); use InterfaceValue instead", types.TypeString(argType, nil)))
+	}
+	return &Value{
+		Pos:  call.Args[0].Pos(),
+		Out:  info.TypeOf(call.Args[0]),
+		expr: call.Args[0],
+		info: info,
+	}, nil
+}
+
+// processValueOf creates a value from a wire.ValueOf call.
+func processValueOf(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*Value, error) {
+	// Assumes that call.Fun is wire.ValueOf, possibly instantiated.
+
+	if !isSimpleValueExpr(info, call.Args[0]) {
+		return nil, notePosition(fset.Position(call.Pos()), errors.New("argument to ValueOf is too complex"))
+	}
+	return &Value{
+		Pos:   call.Args[0].Pos(),
+		Out:   funcTypeArgs(info, call.Fun)[0],
+		expr:  call.Args[0],
+		info:  info,
+		typed: true,
+	}, nil
+}
+
+// isSimpleValueExpr reports whether expr can be copied into the generated
+// injector, that is, whether it does not call any functions or receive from
+// any channels.
+func isSimpleValueExpr(info *types.Info, expr ast.Expr) bool {
 	ok := true
-	ast.Inspect(call.Args[0], func(node ast.Node) bool {
+	ast.Inspect(expr, func(node ast.Node) bool {
 		switch expr := node.(type) {
-		case nil, *ast.ArrayType, *ast.BasicLit, *ast.BinaryExpr, *ast.ChanType, *ast.CompositeLit, *ast.FuncType, *ast.Ident, *ast.IndexExpr, *ast.InterfaceType, *ast.KeyValueExpr, *ast.MapType, *ast.ParenExpr, *ast.SelectorExpr, *ast.SliceExpr, *ast.StarExpr, *ast.StructType, *ast.TypeAssertExpr:
+		case nil, *ast.ArrayType, *ast.BasicLit, *ast.BinaryExpr, *ast.ChanType, *ast.CompositeLit, *ast.FuncType, *ast.Ident, *ast.IndexExpr, *ast.IndexListExpr, *ast.InterfaceType, *ast.KeyValueExpr, *ast.MapType, *ast.ParenExpr, *ast.SelectorExpr, *ast.SliceExpr, *ast.StarExpr, *ast.StructType, *ast.TypeAssertExpr:
 			// Good!
 		case *ast.UnaryExpr:
 			if expr.Op == token.ARROW {
@@ -955,20 +2432,7 @@ func processValue(fset *token.FileSet, info *types.Info, call *ast.CallExpr) (*V
 		}
 		return true
 	})
-	if !ok {
-		return nil, notePosition(fset.Position(call.Pos()), errors.New("argument to Value is too complex"))
-	}
-	// Result type can't be an interface type; use wire.InterfaceValue for that.
-	argType := info.TypeOf(call.Args[0])
-	if _, isInterfaceType := argType.Underlying().(*types.Interface); isInterfaceType {
-		return nil, notePosition(fset.Position(call.Pos()), fmt.Errorf("argument to Value may not be an interface value (found %s); use InterfaceValue instead", types.TypeString(argType, nil)))
-	}
-	return &Value{
-		Pos:  call.Args[0].Pos(),
-		Out:  info.TypeOf(call.Args[0]),
-		expr: call.Args[0],
-		info: info,
-	}, nil
+	return ok
 }
 
 // processInterfaceValue creates a value from a wire.InterfaceValue call.
@@ -1015,33 +2479,52 @@ func processFieldsOf(fset *token.FileSet, info *types.Info, call *ast.CallExpr)
 		return nil, notePosition(fset.Position(call.Pos()),
 			fmt.Errorf(firstArgReqFormat, types.TypeString(structType, nil)))
 	}
+	return newFields(fset, call.Pos(), structPtr.Elem(), call.Args[1:], firstArgReqFormat)
+}
+
+// processFieldsFrom creates a slice of fields from a wire.FieldsFrom call.
+func processFieldsFrom(fset *token.FileSet, info *types.Info, call *ast.CallExpr) ([]*Field, error) {
+	// Assumes that call.Fun is an instantiation of wire.FieldsFrom.
+
+	if len(call.Args) < 1 {
+		return nil, notePosition(fset.Position(call.Pos()),
+			errors.New("call to FieldsFrom must specify fields to be extracted"))
+	}
+	const typeArgReqFormat = "type argument to FieldsFrom must be a struct or a pointer to a struct; found %s"
+	return newFields(fset, call.Pos(), funcTypeArgs(info, call.Fun)[0], call.Args, typeArgReqFormat)
+}
 
+// newFields creates a slice of fields named by fieldArgs from parent, which
+// must be a struct or a pointer to a struct. reqFormat is the error message
+// format to use if it is not.
+func newFields(fset *token.FileSet, pos token.Pos, parent types.Type, fieldArgs []ast.Expr, reqFormat string) ([]*Field, error) {
 	var struc *types.Struct
 	isPtrToStruct := false
-	switch t := structPtr.Elem().Underlying().(type) {
+	switch t := parent.Underlying().(type) {
 	case *types.Pointer:
+		var ok bool
 		struc, ok = t.Elem().Underlying().(*types.Struct)
 		if !ok {
-			return nil, notePosition(fset.Position(call.Pos()),
-				fmt.Errorf(firstArgReqFormat, types.TypeString(struc, nil)))
+			return nil, notePosition(fset.Position(pos),
+				fmt.Errorf(reqFormat, types.TypeString(struc, nil)))
 		}
 		isPtrToStruct = true
 	case *types.Struct:
 		struc = t
 	default:
-		return nil, notePosition(fset.Position(call.Pos()),
-			fmt.Errorf(firstArgReqFormat, types.TypeString(t, nil)))
+		return nil, notePosition(fset.Position(pos),
+			fmt.Errorf(reqFormat, types.TypeString(t, nil)))
 	}
-	if struc.NumFields() < len(call.Args)-1 {
-		return nil, notePosition(fset.Position(call.Pos()),
+	if struc.NumFields() < len(fieldArgs) {
+		return nil, notePosition(fset.Position(pos),
 			fmt.Errorf("fields number exceeds the number available in the struct which has %d fields", struc.NumFields()))
 	}
 
-	fields := make([]*Field, 0, len(call.Args)-1)
-	for i := 1; i < len(call.Args); i++ {
-		v, err := checkField(call.Args[i], struc)
+	fields := make([]*Field, 0, len(fieldArgs))
+	for _, arg := range fieldArgs {
+		v, err := checkField(arg, struc)
 		if err != nil {
-			return nil, notePosition(fset.Position(call.Pos()), err)
+			return nil, notePosition(fset.Position(pos), err)
 		}
 		out := []types.Type{v.Type()}
 		if isPtrToStruct {
@@ -1050,7 +2533,7 @@ func processFieldsOf(fset *token.FileSet, info *types.Info, call *ast.CallExpr)
 			out = append(out, types.NewPointer(v.Type()))
 		}
 		fields = append(fields, &Field{
-			Parent: structPtr.Elem(),
+			Parent: parent,
 			Name:   v.Name(),
 			Pkg:    v.Pkg(),
 			Pos:    v.Pos(),
@@ -1162,11 +2645,12 @@ type ProvidedType struct {
 	v *Value
 	a *InjectorArg
 	f *Field
+	m *Multibinding
 }
 
 // IsNil reports whether pt is the zero value.
 func (pt ProvidedType) IsNil() bool {
-	return pt.p == nil && pt.v == nil && pt.a == nil && pt.f == nil
+	return pt.p == nil && pt.v == nil && pt.a == nil && pt.f == nil && pt.m == nil
 }
 
 // Type returns the output type.
@@ -1200,6 +2684,11 @@ func (pt ProvidedType) IsField() bool {
 	return pt.f != nil
 }
 
+// IsMultibinding reports whether pt points to a Multibinding.
+func (pt ProvidedType) IsMultibinding() bool {
+	return pt.m != nil
+}
+
 // Provider returns pt as a Provider pointer. It panics if pt does not point
 // to a Provider.
 func (pt ProvidedType) Provider() *Provider {
@@ -1236,6 +2725,15 @@ func (pt ProvidedType) Field() *Field {
 	return pt.f
 }
 
+// Multibinding returns pt as a Multibinding pointer. It panics if pt does not
+// point to a Multibinding.
+func (pt ProvidedType) Multibinding() *Multibinding {
+	if pt.m == nil {
+		panic("ProvidedType does not hold a Multibinding")
+	}
+	return pt.m
+}
+
 // bindShouldUsePointer loads the wire package the user is importing from their
 // injector. The call is a wire marker function call.
 func bindShouldUsePointer(info *types.Info, call *ast.CallExpr) bool {
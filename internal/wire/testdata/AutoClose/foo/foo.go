@@ -0,0 +1,69 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+func main() {
+	svc, cleanup, err := injectService()
+	if err != nil {
+		fmt.Println("injectService:", err)
+		return
+	}
+	fmt.Println(svc.db.name, svc.conn.name)
+	fmt.Println("cleanup:", cleanup())
+
+	conn, cleanup2 := injectConn()
+	fmt.Println(conn.name)
+	fmt.Println("cleanup2:", cleanup2())
+}
+
+type DB struct {
+	name string
+}
+
+func (db *DB) Close() error {
+	fmt.Println("close", db.name)
+	return errors.New("db: close failed")
+}
+
+func OpenDB() (*DB, error) {
+	return &DB{name: "db"}, nil
+}
+
+type Conn struct {
+	name string
+}
+
+func (c *Conn) Close() error {
+	fmt.Println("close", c.name)
+	return nil
+}
+
+func Dial(db *DB) *Conn {
+	return &Conn{name: "conn"}
+}
+
+type Service struct {
+	db   *DB
+	conn *Conn
+}
+
+func NewService(db *DB, conn *Conn) *Service {
+	return &Service{db: db, conn: conn}
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+func main() {
+	app, cleanup, err := injectApp(context.Background(), func() {})
+	if err != nil {
+		fmt.Println("injectApp:", err)
+		return
+	}
+	fmt.Println(app.name)
+	cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, _, err = injectApp(ctx, cancel)
+	fmt.Println("injectApp:", err)
+}
+
+type Listener struct{}
+
+// NewListener stands in for a provider that is running when the
+// injector's context is cancelled.
+func NewListener(cancel context.CancelFunc) (*Listener, func()) {
+	cancel()
+	return &Listener{}, func() { fmt.Println("close listener") }
+}
+
+type App struct {
+	name string
+}
+
+func NewApp(ctx context.Context, l *Listener) (*App, error) {
+	return &App{name: "app"}, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+func main() {
+	app, cleanup, err := injectApp()
+	if err != nil {
+		fmt.Println("injectApp:", err)
+		return
+	}
+	fmt.Println(app.db.name, app.cache.name, app.log)
+	fmt.Println("cleanup:", cleanup(context.Background()))
+
+	_, cleanup2, err := injectCache()
+	if err != nil {
+		fmt.Println("injectCache:", err)
+		return
+	}
+	fmt.Println("cleanup:", cleanup2())
+
+	_, _, err = injectBroken()
+	fmt.Println("injectBroken:", err)
+}
+
+type Log string
+
+func NewLog() (Log, func()) {
+	return "log", func() { fmt.Println("close log") }
+}
+
+type DB struct {
+	name string
+}
+
+func NewDB(log Log) (*DB, func(context.Context) error, error) {
+	return &DB{name: "db"}, func(ctx context.Context) error {
+		fmt.Println("close db")
+		return errors.New("db: close failed")
+	}, nil
+}
+
+type Cache struct {
+	name string
+}
+
+func NewCache(log Log) (*Cache, func() error) {
+	return &Cache{name: "cache"}, func() error {
+		fmt.Println("close cache")
+		return errors.New("cache: close failed")
+	}
+}
+
+type App struct {
+	db    *DB
+	cache *Cache
+	log   Log
+}
+
+func NewApp(db *DB, cache *Cache, log Log) *App {
+	return &App{db: db, cache: cache, log: log}
+}
+
+type Broken struct{}
+
+func NewBroken(db *DB) (*Broken, error) {
+	return nil, errors.New("broken")
+}
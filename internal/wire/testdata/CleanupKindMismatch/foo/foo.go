@@ -0,0 +1,49 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+)
+
+func main() {}
+
+type Foo int
+
+type Bar int
+
+type Baz int
+
+func NewFoo() (Foo, func() error) {
+	return 0, func() error { return nil }
+}
+
+func NewBar() (Bar, func(context.Context) error) {
+	return 0, func(context.Context) error { return nil }
+}
+
+func NewBaz() (Baz, func(context.Context)) {
+	return 0, func(context.Context) {}
+}
+
+type Closer struct{}
+
+func (*Closer) Close() error {
+	return nil
+}
+
+func NewCloser() *Closer {
+	return &Closer{}
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func main() {
+	app, err := injectApp()
+	fmt.Println(app.Handler.Handle("hello"), err)
+}
+
+type Handler interface {
+	Handle(msg string) string
+}
+
+type baseHandler struct{}
+
+func (baseHandler) Handle(msg string) string {
+	return msg
+}
+
+func NewBaseHandler() *baseHandler {
+	return &baseHandler{}
+}
+
+type Logger struct {
+	Prefix string
+}
+
+func NewLogger() *Logger {
+	return &Logger{Prefix: "log"}
+}
+
+type funcHandler func(string) string
+
+func (f funcHandler) Handle(msg string) string {
+	return f(msg)
+}
+
+func WithLogging(h Handler, l *Logger) Handler {
+	return funcHandler(func(msg string) string {
+		return l.Prefix + "(" + h.Handle(msg) + ")"
+	})
+}
+
+func WithUpper(h Handler) (Handler, error) {
+	return funcHandler(func(msg string) string {
+		return strings.ToUpper(h.Handle(msg))
+	}), nil
+}
+
+type App struct {
+	Handler Handler
+}
+
+func NewApp(h Handler) *App {
+	return &App{Handler: h}
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+func main() {}
+
+type Foo int
+
+type Bar int
+
+type Baz int
+
+func NewFoo() Foo {
+	return 1
+}
+
+func NewBar(foo Foo) Bar {
+	return Bar(foo)
+}
+
+func NewBaz() Baz {
+	return 2
+}
+
+func DecorateFoo(foo Foo) Foo {
+	return foo + 1
+}
+
+func DecorateFooWithBar(foo Foo, bar Bar) Foo {
+	return foo + Foo(bar)
+}
+
+func NotDecorator(bar Bar) Foo {
+	return Foo(bar)
+}
+
+type Qux struct{}
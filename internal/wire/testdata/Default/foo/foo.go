@@ -0,0 +1,105 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/wire"
+)
+
+func main() {
+	injectDefault().Print()
+	injectCustom().Print()
+	injectArg(&FakeClock{}).Print()
+	both := injectBothLibs()
+	both.Service.Print()
+	fmt.Println(both.Reporter.Logger.Name)
+}
+
+type Clock interface {
+	Now() string
+}
+
+type SystemClock struct{}
+
+func NewSystemClock() *SystemClock {
+	return &SystemClock{}
+}
+
+func (*SystemClock) Now() string {
+	return "system"
+}
+
+type FakeClock struct{}
+
+func NewFakeClock() *FakeClock {
+	return &FakeClock{}
+}
+
+func (*FakeClock) Now() string {
+	return "fake"
+}
+
+type Logger struct {
+	Name string
+}
+
+func NewLibLogger() *Logger {
+	return &Logger{Name: "lib"}
+}
+
+func NewOtherLogger() *Logger {
+	return &Logger{Name: "other"}
+}
+
+func NewAppLogger() *Logger {
+	return &Logger{Name: "app"}
+}
+
+type Service struct {
+	Clock  Clock
+	Logger *Logger
+}
+
+func NewService(c Clock, l *Logger) *Service {
+	return &Service{Clock: c, Logger: l}
+}
+
+func (s *Service) Print() {
+	fmt.Println(s.Clock.Now(), s.Logger.Name)
+}
+
+var LibSet = wire.NewSet(
+	NewService,
+	wire.Default(NewSystemClock),
+	wire.Default(wire.Bind(new(Clock), new(*SystemClock))),
+	wire.Default(NewLibLogger),
+)
+
+type Reporter struct {
+	Logger *Logger
+}
+
+func NewReporter(l *Logger) *Reporter {
+	return &Reporter{Logger: l}
+}
+
+var OtherLibSet = wire.NewSet(NewReporter, wire.Default(NewOtherLogger))
+
+type App struct {
+	Service  *Service
+	Reporter *Reporter
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/google/wire"
+)
+
+func main() {}
+
+type Clock interface {
+	Now() string
+}
+
+type SystemClock struct{}
+
+func (*SystemClock) Now() string {
+	return "system"
+}
+
+type Logger struct{}
+
+func NewLibLogger() *Logger {
+	return &Logger{}
+}
+
+func NewOtherLogger() *Logger {
+	return &Logger{}
+}
+
+type Service struct{}
+
+func NewService(l *Logger) *Service {
+	return &Service{}
+}
+
+var LibSet = wire.NewSet(NewService, wire.Default(NewLibLogger))
+
+var OtherLibSet = wire.NewSet(wire.Default(NewOtherLogger))
@@ -0,0 +1,81 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+func main() {
+	app, cleanup := injectApp()
+	s, err := app.NewSession("alice")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(s.UserID, s.DB == app.DB, s.Logger.Prefix)
+	if _, err := app.NewSession(""); err != nil {
+		fmt.Println(err)
+	}
+	tx, txCleanup := app.NewTx("t1")
+	fmt.Println(tx.Name)
+	txCleanup()
+	cleanup()
+}
+
+type DB struct{}
+
+func NewDB() (*DB, func()) {
+	fmt.Println("open db")
+	return &DB{}, func() { fmt.Println("close db") }
+}
+
+type Logger struct {
+	Prefix string
+}
+
+func NewLogger() *Logger {
+	return &Logger{Prefix: "app"}
+}
+
+type Session struct {
+	DB     *DB
+	UserID string
+	Logger *Logger
+}
+
+func NewSession(db *DB, userID string, logger *Logger) (*Session, error) {
+	if userID == "" {
+		return nil, errors.New("empty user ID")
+	}
+	return &Session{DB: db, UserID: userID, Logger: logger}, nil
+}
+
+type Tx struct {
+	Name string
+}
+
+type TxFactory func(name string) (*Tx, func())
+
+func NewTx(db *DB, name string) (*Tx, func()) {
+	fmt.Println("begin", name)
+	return &Tx{Name: name}, func() { fmt.Println("end", name) }
+}
+
+type App struct {
+	DB         *DB
+	NewSession func(string) (*Session, error)
+	NewTx      TxFactory
+}
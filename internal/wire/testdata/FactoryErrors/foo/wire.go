@@ -0,0 +1,51 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+
+package main
+
+import (
+	"github.com/google/wire"
+)
+
+func injectNoError(db *DB) func(string) *Session {
+	wire.Build(wire.Factory(new(func(string) *Session), NewSession))
+	return nil
+}
+
+func injectWrongParam(db *DB) func(int) (*Session, error) {
+	wire.Build(wire.Factory(new(func(int) (*Session, error)), NewSession))
+	return nil
+}
+
+func injectWrongOutput(db *DB) func(string) (*Tx, error) {
+	wire.Build(wire.Factory(new(func(string) (*Tx, error)), NewSession))
+	return nil
+}
+
+func injectNoCleanup(db *DB) func(string) *Tx {
+	wire.Build(wire.Factory(new(func(string) *Tx), NewTx))
+	return nil
+}
+
+func injectNotFunc(db *DB) *Session {
+	wire.Build(wire.Factory(new(*Session), NewSession))
+	return nil
+}
+
+func injectMissing() func(string) (*Session, error) {
+	wire.Build(wire.Factory(new(func(string) (*Session, error)), NewSession))
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+func main() {
+	s, err := InitService[Greeter](Greeter{})
+	if err != nil {
+		fmt.Println("ERROR:", err)
+		return
+	}
+	fmt.Println(s.Name, s.Handler.Handle())
+	b := InitBox[Greeter](Greeter{})
+	fmt.Println(b.Val.Handle())
+	if _, err := InitHandler[Greeter](); err != nil {
+		fmt.Println("ERROR:", err)
+	}
+}
+
+type Handler interface {
+	Handle() string
+}
+
+type Greeter struct{}
+
+func (Greeter) Handle() string {
+	return "hello"
+}
+
+type Name string
+
+func provideName() Name {
+	return "greeter"
+}
+
+type Service[T Handler] struct {
+	Name    Name
+	Handler T
+}
+
+func NewService[T Handler](name Name, h T) (*Service[T], error) {
+	return &Service[T]{Name: name, Handler: h}, nil
+}
+
+type Box[T any] struct {
+	Val T
+}
+
+func NewBox[T any](v T) *Box[T] {
+	return &Box[T]{Val: v}
+}
+
+func newHandler[T Handler](name Name) (T, error) {
+	var zero T
+	return zero, errors.New("no handler for " + string(name))
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/wire"
+)
+
+func main() {
+	app := injectApp()
+	fmt.Println(app.Users.Repo.Table, app.Orders.Table)
+}
+
+type Entity interface {
+	TableName() string
+}
+
+type User struct{}
+
+func (User) TableName() string { return "users" }
+
+type Order struct{}
+
+func (Order) TableName() string { return "orders" }
+
+type DB struct{}
+
+func provideDB() *DB {
+	return new(DB)
+}
+
+type Repo[T Entity] struct {
+	DB    *DB
+	Table string
+}
+
+func NewRepo[T Entity](db *DB) *Repo[T] {
+	var ent T
+	return &Repo[T]{DB: db, Table: ent.TableName()}
+}
+
+type Cache[T Entity] struct {
+	Repo *Repo[T]
+}
+
+func NewCache[T Entity](repo *Repo[T]) *Cache[T] {
+	return &Cache[T]{Repo: repo}
+}
+
+type App struct {
+	Users  *Cache[User]
+	Orders *Repo[Order]
+}
+
+func NewApp(users *Cache[User], orders *Repo[Order]) *App {
+	return &App{Users: users, Orders: orders}
+}
+
+var Set = wire.NewSet(wire.Generic(NewRepo[Entity]), wire.Generic(NewCache[Entity]))
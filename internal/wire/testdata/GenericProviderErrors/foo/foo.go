@@ -0,0 +1,67 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/wire"
+)
+
+func main() {}
+
+type Label string
+
+type Color int
+
+func (c Color) String() string {
+	return fmt.Sprintf("color #%d", int(c))
+}
+
+func NewLabel[T fmt.Stringer](v T) Label {
+	return Label(v.String())
+}
+
+type Repo[T any] struct{}
+
+func NewRepo[T any]() *Repo[T] {
+	return new(Repo[T])
+}
+
+func NewIntRepo[T ~int]() *Repo[T] {
+	return new(Repo[T])
+}
+
+type Box[T any] struct {
+	Val T
+}
+
+func NewBox[T any](b Box[Box[T]]) Box[T] {
+	return b.Val
+}
+
+type A[T any] struct{}
+
+type B[T any] struct{}
+
+func NewA[T any](B[T]) A[T] {
+	return A[T]{}
+}
+
+func NewB[T any](A[T]) B[T] {
+	return B[T]{}
+}
+
+var RepoSet = wire.NewSet(wire.Generic(NewRepo[any]))
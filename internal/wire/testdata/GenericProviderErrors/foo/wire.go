@@ -0,0 +1,47 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+
+package main
+
+import (
+	"github.com/google/wire"
+)
+
+func injectLabel() Label {
+	wire.Build(wire.Generic(NewLabel[Color]))
+	return ""
+}
+
+func injectRepo() *Repo[int] {
+	wire.Build(RepoSet, wire.Generic(NewIntRepo[int]))
+	return nil
+}
+
+func injectStringRepo() *Repo[string] {
+	// NewIntRepo can't be instantiated with string, so it is unused.
+	wire.Build(RepoSet, wire.Generic(NewIntRepo[int]))
+	return nil
+}
+
+func injectBox() Box[int] {
+	wire.Build(wire.Generic(NewBox[any]))
+	return Box[int]{}
+}
+
+func injectA() A[string] {
+	wire.Build(wire.Generic(NewA[any]), wire.Generic(NewB[any]))
+	return A[string]{}
+}
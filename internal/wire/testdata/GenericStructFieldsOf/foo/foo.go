@@ -0,0 +1,80 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+)
+
+func main() {
+	s := injectService()
+	fmt.Println(s.Cache.Items, s.Store.Get(), s.DB, s.Pair)
+	fmt.Println(injectOrderStore("orders.db").Get())
+	fmt.Println(firstItem(s.Cache.Items))
+}
+
+type User struct {
+	Name string
+}
+
+type Order struct {
+	ID int
+}
+
+type Prod struct{}
+
+type DSN string
+
+type Config[Env any] struct {
+	DB   DSN
+	Port int
+}
+
+func provideConfig() Config[Prod] {
+	return Config[Prod]{DB: "prod.db", Port: 8080}
+}
+
+type Cache[T any] struct {
+	Items []T
+}
+
+type Store[T any] interface {
+	Get() T
+}
+
+type SQLStore[T any] struct {
+	DB DSN
+}
+
+func (s *SQLStore[T]) Get() T {
+	var zero T
+	return zero
+}
+
+func NewSQLStore[T any](db DSN) *SQLStore[T] {
+	return &SQLStore[T]{DB: db}
+}
+
+type Pair[K comparable, V any] struct {
+	Key K
+	Val V
+}
+
+type Service struct {
+	Cache *Cache[string]
+	Store Store[User]
+	DB    DSN
+	Pair  Pair[string, int]
+}
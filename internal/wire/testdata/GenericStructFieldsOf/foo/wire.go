@@ -0,0 +1,48 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+
+package main
+
+import (
+	"github.com/google/wire"
+)
+
+func injectService() *Service {
+	wire.Build(
+		provideConfig,
+		wire.FieldsOf(new(Config[Prod]), "DB"),
+		NewSQLStore[User],
+		wire.Bind(new(Store[User]), new(*SQLStore[User])),
+		wire.Value([]string{"a", "b"}),
+		wire.Struct(new(Cache[string]), "*"),
+		wire.Value(Pair[string, int]{Key: "answer", Val: 42}),
+		wire.Struct(new(Service), "*"),
+	)
+	return nil
+}
+
+func injectOrderStore(db DSN) Store[Order] {
+	wire.Build(
+		wire.Generic(NewSQLStore[any]),
+		wire.Bind(new(Store[Order]), new(*SQLStore[Order])),
+	)
+	return nil
+}
+
+// firstItem is copied into the generated file with its type parameters.
+func firstItem[T any](items []T) T {
+	return items[0]
+}
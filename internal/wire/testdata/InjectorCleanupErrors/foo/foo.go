@@ -0,0 +1,72 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+func main() {
+	srv, cleanup, err := injectServer()
+	if err != nil {
+		fmt.Println("injectServer:", err)
+		return
+	}
+	fmt.Println(srv.name)
+	fmt.Println("cleanup:", cleanup())
+
+	_, _, err = injectFailing()
+	fmt.Println("injectFailing:", err)
+}
+
+type Logger struct{}
+
+func NewLogger() (*Logger, func()) {
+	return &Logger{}, func() { fmt.Println("close logger") }
+}
+
+type Queue struct{}
+
+func NewQueue(log *Logger) (*Queue, func(context.Context) error) {
+	return &Queue{}, func(ctx context.Context) error {
+		fmt.Println("close queue")
+		return errors.New("queue: close failed")
+	}
+}
+
+type Store struct{}
+
+func NewStore(log *Logger) (*Store, func() error) {
+	return &Store{}, func() error {
+		fmt.Println("close store")
+		return errors.New("store: close failed")
+	}
+}
+
+type Server struct {
+	name string
+}
+
+func NewServer(q *Queue, s *Store) (*Server, error) {
+	return &Server{name: "server"}, nil
+}
+
+type Failing struct{}
+
+func NewFailing(s *Store) (*Failing, error) {
+	return nil, errors.New("failing")
+}
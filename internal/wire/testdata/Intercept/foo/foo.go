@@ -0,0 +1,81 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/wire/wireruntime"
+)
+
+func main() {
+	app := injectApp()
+	fmt.Println(app.Store.Get("key"))
+	app.Store.Log("%d items", 2)
+	fmt.Println(app.Store.Close())
+}
+
+type Store interface {
+	Get(key string) (string, bool)
+	Log(format string, args ...interface{})
+	Close() error
+}
+
+type mapStore struct {
+	m map[string]string
+}
+
+func NewMapStore() *mapStore {
+	return &mapStore{m: map[string]string{"key": "value"}}
+}
+
+func (s *mapStore) Get(key string) (string, bool) {
+	v, ok := s.m[key]
+	return v, ok
+}
+
+func (s *mapStore) Log(format string, args ...interface{}) {
+	fmt.Printf(format+"\n", args...)
+}
+
+func (s *mapStore) Close() error {
+	return nil
+}
+
+type printInterceptor struct{}
+
+func NewInterceptor() wireruntime.Interceptor {
+	return printInterceptor{}
+}
+
+func (printInterceptor) Intercept(call *wireruntime.MethodCall, invoke func()) {
+	fmt.Println("call", call.Interface, call.Method, call.Args)
+	if call.Method == "Close" {
+		// Inject a fault without calling the method.
+		call.Results = []interface{}{errors.New("injected fault")}
+		return
+	}
+	invoke()
+	fmt.Println("results", call.Results)
+}
+
+type App struct {
+	Store Store
+}
+
+func NewApp(s Store) *App {
+	return &App{Store: s}
+}
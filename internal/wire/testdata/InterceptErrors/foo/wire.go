@@ -0,0 +1,41 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+
+package main
+
+import (
+	"github.com/google/wire"
+)
+
+func injectNotInterface() *foo {
+	wire.Build(NewFoo, NewInterceptor, wire.Intercept(new(*foo)))
+	return nil
+}
+
+func injectNotBound() Fooer {
+	wire.Build(NewFooer, NewInterceptor, wire.Intercept(new(Fooer)))
+	return nil
+}
+
+func injectNoInterceptor() Fooer {
+	wire.Build(NewFoo, wire.Bind(new(Fooer), new(*foo)), wire.Intercept(new(Fooer)))
+	return nil
+}
+
+func injectUnused() *foo {
+	wire.Build(NewFoo, wire.Bind(new(Fooer), new(*foo)), NewInterceptor, wire.Intercept(new(Fooer)))
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/wire"
+)
+
+func main() {
+	cfg := &Config{DSN: "db"}
+	cli, cleanup, err := injectCLI(cfg)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("built")
+	db1, _ := cli.DB()
+	db2, _ := cli.DB()
+	fmt.Println(db1 == db2, db1.DSN)
+	c1, _ := cli.NewConn()
+	c2, _ := cli.NewConn()
+	fmt.Println(c1 == c2)
+	_, txCleanup, _ := cli.NewTx()
+	txCleanup()
+	cleanup()
+
+	cli, cleanup, _ = injectCLI(cfg)
+	cleanup()
+	fmt.Println("unused")
+	_, err = cli.DB()
+	fmt.Println(err)
+
+	newConn := injectConnFactory(cfg)
+	newConn()
+
+	shared, cleanup, err := injectShared()
+	if err != nil {
+		panic(err)
+	}
+	db, _ := shared.DB()
+	fmt.Println(shared.Config == db.Config)
+	cleanup()
+}
+
+func NewConfig() *Config {
+	fmt.Println("new config")
+	return &Config{DSN: "shared"}
+}
+
+type Config struct {
+	DSN string
+}
+
+type DB struct {
+	DSN    string
+	Config *Config
+}
+
+func NewDB(cfg *Config) (*DB, func(), error) {
+	fmt.Println("open db")
+	return &DB{DSN: cfg.DSN, Config: cfg}, func() { fmt.Println("close db") }, nil
+}
+
+type Conn struct {
+	ID int
+}
+
+func NewConn(cfg *Config) (*Conn, error) {
+	fmt.Println("new conn")
+	return &Conn{}, nil
+}
+
+type Tx struct{}
+
+func NewTx(conn *Conn) (*Tx, func()) {
+	fmt.Println("begin tx")
+	return &Tx{}, func() { fmt.Println("end tx") }
+}
+
+type CLI struct {
+	DB      wire.Lazy[*DB]
+	NewConn func() (*Conn, error)
+	NewTx   func() (*Tx, func(), error)
+}
+
+func NewCLI(db wire.Lazy[*DB], newConn func() (*Conn, error), newTx func() (*Tx, func(), error)) *CLI {
+	return &CLI{DB: db, NewConn: newConn, NewTx: newTx}
+}
+
+// SharedCLI takes the lazy value first, so the *Config that it shares with
+// the DB is needed by the closure before the injector needs it.
+type SharedCLI struct {
+	DB     wire.Lazy[*DB]
+	Config *Config
+}
+
+func NewSharedCLI(db wire.Lazy[*DB], cfg *Config) *SharedCLI {
+	return &SharedCLI{DB: db, Config: cfg}
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/google/wire"
+)
+
+func main() {}
+
+type Foo struct{}
+
+type Bar struct{}
+
+type Baz struct{}
+
+func NewFoo(lazy wire.Lazy[*Foo]) *Foo {
+	return &Foo{}
+}
+
+func NewBar() (*Bar, func()) {
+	return &Bar{}, func() {}
+}
+
+func NewBaz(s string) *Baz {
+	return &Baz{}
+}
+
+type App struct{}
+
+func NewAppWithBar(newBar func() (*Bar, error)) *App {
+	return &App{}
+}
+
+func NewAppWithLazyBar(bar wire.Lazy[*Bar]) *App {
+	return &App{}
+}
+
+func NewAppWithBaz(baz wire.Lazy[*Baz]) *App {
+	return &App{}
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+func main() {
+	ctx := context.Background()
+	app := injectApp()
+	fmt.Println(app.Value.Name)
+	fmt.Println("start:", app.Start(ctx))
+	fmt.Println("stop:", app.Stop(ctx))
+
+	traced := injectTraced()
+	fmt.Println("start:", traced.Start(ctx))
+	fmt.Println("stop:", traced.Stop(ctx))
+
+	broken := injectBroken()
+	fmt.Println("start:", broken.Start(ctx))
+	fmt.Println("stop:", broken.Stop(ctx))
+}
+
+type Config struct {
+	Name string
+}
+
+type DB struct{}
+
+type Server struct {
+	Name string
+}
+
+type Worker struct{}
+
+func NewConfig() *Config {
+	return &Config{Name: "server"}
+}
+
+func NewDB(cfg *Config) *DB {
+	return &DB{}
+}
+
+func (*DB) Start(ctx context.Context) error {
+	fmt.Println("start db")
+	return nil
+}
+
+func (*DB) Stop(ctx context.Context) error {
+	fmt.Println("stop db")
+	return nil
+}
+
+// TraceDB decorates the DB. The decorated DB is started only once.
+func TraceDB(db *DB) *DB {
+	fmt.Println("trace db")
+	return db
+}
+
+func NewServer(cfg *Config, db *DB) *Server {
+	return &Server{Name: cfg.Name}
+}
+
+func (*Server) Start(ctx context.Context) error {
+	fmt.Println("start server")
+	return nil
+}
+
+func (*Server) Stop(ctx context.Context) error {
+	fmt.Println("stop server")
+	return nil
+}
+
+func NewWorker(db *DB) *Worker {
+	return &Worker{}
+}
+
+func (*Worker) Start(ctx context.Context) error {
+	return errors.New("worker failed")
+}
+
+func (*Worker) Stop(ctx context.Context) error {
+	fmt.Println("stop worker")
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+func main() {
+	for _, m := range injectMiddleware() {
+		fmt.Println(m.Name())
+	}
+	plugins := injectPlugins()
+	var names []string
+	for k := range plugins {
+		names = append(names, string(k))
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		fmt.Println(k, plugins[PluginKind(k)].ID)
+	}
+}
+
+type Middleware interface {
+	Name() string
+}
+
+type authMW struct {
+	realm string
+}
+
+func (m *authMW) Name() string { return "auth:" + m.realm }
+
+type logMW struct{}
+
+func (logMW) Name() string { return "log" }
+
+type Realm string
+
+func NewAuthMW(realm Realm) *authMW {
+	return &authMW{realm: string(realm)}
+}
+
+type Plugin struct {
+	ID int
+}
+
+type PluginKind string
+
+const (
+	Cache PluginKind = "cache"
+	Auth  PluginKind = "auth"
+)
+
+func NewCachePlugin() *Plugin {
+	return &Plugin{ID: 1}
+}
+
+func NewAuthPlugin() *Plugin {
+	return &Plugin{ID: 2}
+}
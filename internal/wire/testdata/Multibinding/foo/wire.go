@@ -0,0 +1,45 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+
+package main
+
+import (
+	"github.com/google/wire"
+)
+
+var authSet = wire.NewSet(
+	wire.Value(Realm("admin")),
+	wire.Into(new([]Middleware), NewAuthMW),
+)
+
+var logSet = wire.NewSet(
+	wire.Into(new([]Middleware), wire.InterfaceValue(new(Middleware), logMW{})),
+)
+
+func injectMiddleware() []Middleware {
+	wire.Build(authSet, logSet)
+	return nil
+}
+
+var pluginSet = wire.NewSet(
+	wire.IntoMap(new(map[PluginKind]*Plugin), Cache, NewCachePlugin),
+	wire.IntoMap(new(map[PluginKind]*Plugin), Auth, NewAuthPlugin),
+)
+
+func injectPlugins() map[PluginKind]*Plugin {
+	wire.Build(pluginSet)
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+
+package main
+
+import (
+	"github.com/google/wire"
+)
+
+var pluginKey = "a"
+
+func injectDuplicateKey() map[string]*Plugin {
+	wire.Build(
+		wire.IntoMap(new(map[string]*Plugin), "a", NewPlugin),
+		wire.IntoMap(new(map[string]*Plugin), "a", NewPlugin),
+	)
+	return nil
+}
+
+func injectConflict() []*Plugin {
+	wire.Build(
+		NewPlugins,
+		wire.Into(new([]*Plugin), NewPlugin),
+	)
+	return nil
+}
+
+func injectNotAssignable() []Handler {
+	wire.Build(wire.Into(new([]Handler), NewPlugin))
+	return nil
+}
+
+func injectNonConstantKey() map[string]*Plugin {
+	wire.Build(wire.IntoMap(new(map[string]*Plugin), pluginKey, NewPlugin))
+	return nil
+}
+
+func injectNotCollection() *Plugin {
+	wire.Build(wire.Into(new(Plugin), NewPlugin))
+	return nil
+}
+
+func injectUnused() *Plugin {
+	wire.Build(
+		NewPlugin,
+		wire.Into(new([]Unused), NewUnused),
+	)
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+)
+
+func main() {
+	r := injectReplicated()
+	fmt.Println(r.Primary.Addr, r.Replica.Addr, r.Timeout)
+	s := injectStore()
+	fmt.Println(s.primary.Addr, s.replica.Addr, s.log)
+}
+
+type DB struct {
+	Addr string
+}
+
+func NewPrimaryDB() *DB {
+	return &DB{Addr: "primary:5432"}
+}
+
+func NewReplicaDB(addr string) *DB {
+	return &DB{Addr: addr}
+}
+
+type Timeout int
+
+type Replicated struct {
+	Primary *DB `wire:"name=primary"`
+	Replica *DB `wire:"name=replica"`
+	Timeout Timeout
+}
+
+type Logger string
+
+type Store struct {
+	primary *DB
+	replica *DB
+	log     Logger
+}
+
+func NewStore(primary, replica *DB, log Logger) *Store {
+	return &Store{primary: primary, replica: replica, log: log}
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+
+package main
+
+import (
+	"github.com/google/wire"
+)
+
+var dbSet = wire.NewSet(
+	wire.Named("primary", NewPrimaryDB),
+	wire.Named("replica", NewReplicaDB),
+	wire.Value("replica:5432"),
+)
+
+func injectReplicated() Replicated {
+	wire.Build(
+		dbSet,
+		wire.Value(Timeout(30)),
+		wire.Struct(new(Replicated), "*"),
+	)
+	return Replicated{}
+}
+
+func injectStore() *Store {
+	wire.Build(
+		dbSet,
+		wire.Named("log", wire.Value(Logger("stdout"))),
+		wire.NamedParams(NewStore, "primary", "replica", "log"),
+	)
+	return nil
+}
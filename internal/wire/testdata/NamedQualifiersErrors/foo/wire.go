@@ -0,0 +1,82 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+
+package main
+
+import (
+	"github.com/google/wire"
+)
+
+func injectMissing() *Store {
+	// Fails because nothing provides *DB named "secondary".
+	wire.Build(
+		wire.Named("primary", NewPrimaryDB),
+		wire.NamedParams(NewStore, "primary", "secondary"),
+	)
+	return nil
+}
+
+func injectConflict() *Store {
+	// Fails because *DB named "primary" is provided twice.
+	wire.Build(
+		wire.Named("primary", NewPrimaryDB),
+		wire.Named("primary", NewReplicaDB),
+		wire.NamedParams(NewStore, "primary", ""),
+	)
+	return nil
+}
+
+func injectUnqualified() *Store {
+	// Fails because the two parameters of NewStore request the same type.
+	wire.Build(NewPrimaryDB, NewStore)
+	return nil
+}
+
+func injectSameNames() *Store {
+	// Fails because both parameters request *DB named "primary".
+	wire.Build(
+		wire.Named("primary", NewPrimaryDB),
+		wire.NamedParams(NewStore, "primary", "primary"),
+	)
+	return nil
+}
+
+func injectWrongCount() *Store {
+	// Fails because NewStore has two parameters.
+	wire.Build(
+		wire.Named("primary", NewPrimaryDB),
+		wire.NamedParams(NewStore, "primary"),
+	)
+	return nil
+}
+
+func injectBadName() *DB {
+	// Fails because "primary db" is not an identifier.
+	wire.Build(wire.Named("primary db", NewPrimaryDB))
+	return nil
+}
+
+func injectBadTag() BadTag {
+	// Fails because "primary-db" is not an identifier.
+	wire.Build(NewPrimaryDB, wire.Struct(new(BadTag), "*"))
+	return BadTag{}
+}
+
+func injectNamedSet() *DB {
+	// Fails because Named may not be applied to a provider set.
+	wire.Build(wire.Named("primary", wire.NewSet(NewPrimaryDB)))
+	return nil
+}
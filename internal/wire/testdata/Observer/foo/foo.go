@@ -0,0 +1,78 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/google/wire/wireruntime"
+)
+
+func main() {
+	o := printObserver{}
+	app, err := injectApp(o)
+	fmt.Println(app.Msg, err)
+	_, err = injectBroken(o)
+	fmt.Println(err)
+	app, err = injectApp(nil)
+	fmt.Println(app.Msg, err)
+	lazy, err := injectLazyApp(o)
+	fmt.Println("built lazy app", err)
+	app, err = lazy.App()
+	fmt.Println(app.Msg, err)
+}
+
+type printObserver struct{}
+
+func (printObserver) ProviderStarted(info wireruntime.ProviderInfo) {
+	fmt.Println("start", info.Name, info.Type)
+}
+
+func (printObserver) ProviderFinished(info wireruntime.ProviderInfo, d time.Duration, err error) {
+	fmt.Println("finish", info.Name, info.Type, err)
+}
+
+type Config struct {
+	Name string
+}
+
+type App struct {
+	Msg string
+}
+
+func NewConfig() *Config {
+	return &Config{Name: "hello"}
+}
+
+func NewApp(cfg *Config) (*App, error) {
+	return &App{Msg: cfg.Name}, nil
+}
+
+type Broken struct{}
+
+func NewBroken(cfg *Config) (*Broken, error) {
+	return nil, errors.New("broken")
+}
+
+type LazyApp struct {
+	App wire.Lazy[*App]
+}
+
+func NewLazyApp(app wire.Lazy[*App]) *LazyApp {
+	return &LazyApp{App: app}
+}
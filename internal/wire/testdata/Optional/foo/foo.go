@@ -0,0 +1,66 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/wire"
+)
+
+func main() {
+	injectServer().Handle()
+	injectServerWithMetrics().Handle()
+}
+
+type Metrics interface {
+	Count(name string)
+}
+
+type printMetrics struct{}
+
+func (printMetrics) Count(name string) {
+	fmt.Println("count", name)
+}
+
+func NewPrintMetrics() printMetrics {
+	return printMetrics{}
+}
+
+type Options struct {
+	Name string
+}
+
+type Server struct {
+	metrics Metrics
+	opts    Options
+}
+
+func NewServer(m Metrics, opts Options) *Server {
+	return &Server{metrics: m, opts: opts}
+}
+
+func (s *Server) Handle() {
+	if s.metrics != nil {
+		s.metrics.Count("handle")
+	}
+	fmt.Printf("handle %q\n", s.opts.Name)
+}
+
+var ServerSet = wire.NewSet(
+	NewServer,
+	wire.Optional(new(Metrics)),
+	wire.Optional(new(Options)),
+)
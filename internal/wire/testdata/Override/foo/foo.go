@@ -0,0 +1,75 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+)
+
+func main() {
+	fmt.Println(injectFakeClock().Greet())
+	fmt.Println(injectGreeting().Greet())
+	fmt.Println(injectStoppedClock().Greet())
+}
+
+type Clock interface {
+	Now() int
+}
+
+type RealClock struct {
+	stopped bool
+}
+
+func (c *RealClock) Now() int {
+	if c.stopped {
+		return 0
+	}
+	return 1234
+}
+
+func NewRealClock() *RealClock {
+	return &RealClock{}
+}
+
+type FakeClock struct{}
+
+func (FakeClock) Now() int { return 42 }
+
+func NewFakeClock() Clock {
+	return FakeClock{}
+}
+
+func NewStoppedClock() *RealClock {
+	return &RealClock{stopped: true}
+}
+
+type Greeting string
+
+func NewGreeting() Greeting {
+	return "hello"
+}
+
+type App struct {
+	clock    Clock
+	greeting Greeting
+}
+
+func NewApp(clock Clock, greeting Greeting) *App {
+	return &App{clock: clock, greeting: greeting}
+}
+
+func (a *App) Greet() string {
+	return fmt.Sprintf("%s at %d", a.greeting, a.clock.Now())
+}
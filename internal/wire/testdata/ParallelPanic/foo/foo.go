@@ -0,0 +1,84 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+func main() {
+	srv, cleanup, err := injectServer(&Config{})
+	if err != nil {
+		fmt.Println("injectServer:", err)
+		return
+	}
+	fmt.Println(srv.db.name, srv.cache.name, srv.queue.name)
+	cleanup()
+
+	_, _, err = injectServer(&Config{PanicCache: true})
+	fmt.Println("injectServer:", err)
+	closed.Lock()
+	fmt.Println("closed:", closed.names)
+	closed.Unlock()
+}
+
+var closed struct {
+	sync.Mutex
+	names []string
+}
+
+func closer(name string) func() {
+	return func() {
+		closed.Lock()
+		defer closed.Unlock()
+		closed.names = append(closed.names, name)
+	}
+}
+
+type Config struct {
+	PanicCache bool
+}
+
+type Conn struct {
+	name string
+}
+
+type DB Conn
+
+type Cache Conn
+
+type Queue Conn
+
+func OpenDB(cfg *Config) (*DB, func(), error) {
+	return &DB{name: "db"}, closer("db"), nil
+}
+
+func DialCache(cfg *Config) (*Cache, func()) {
+	if cfg.PanicCache {
+		panic("cache unavailable")
+	}
+	return &Cache{name: "cache"}, closer("cache")
+}
+
+func DialQueue(cfg *Config, cache *Cache) (*Queue, func(), error) {
+	return &Queue{name: "queue"}, closer("queue"), nil
+}
+
+type Server struct {
+	db    *DB
+	cache *Cache
+	queue *Queue
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+func main() {
+	_, err := injectConfig("")
+	fmt.Println(err)
+	cfg, err := injectConfig("localhost")
+	fmt.Println(cfg.Addr, err)
+	_, _, err = injectServer("")
+	fmt.Println(err)
+	s, cleanup, err := injectServer("localhost")
+	fmt.Println(s.Ready, err)
+	cleanup()
+}
+
+type Addr string
+
+type Config struct {
+	Addr Addr
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.Addr == "" {
+		return errors.New("missing address")
+	}
+	return nil
+}
+
+type Server struct {
+	Ready bool
+}
+
+func NewServer(cfg *Config) (*Server, func()) {
+	return &Server{}, func() { fmt.Println("cleanup server") }
+}
+
+func (s *Server) Init() error {
+	s.Ready = true
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+
+package main
+
+import (
+	"github.com/google/wire"
+)
+
+func injectBothMethods() *Bar {
+	wire.Build(wire.Struct(new(Bar)), wire.PostConstruct(new(*Bar)))
+	return nil
+}
+
+func injectNoMethod() Baz {
+	wire.Build(wire.Struct(new(Baz)), wire.PostConstruct(new(Baz)))
+	return Baz{}
+}
+
+func injectNotProvided() *Foo {
+	wire.Build(NewFoo, wire.PostConstruct(new(Qux)))
+	return nil
+}
+
+func injectValue() Qux {
+	wire.Build(wire.Value(Qux{}), wire.PostConstruct(new(Qux)))
+	return Qux{}
+}
+
+func injectUnused() *Foo {
+	wire.Build(NewFoo, NewQux, wire.PostConstruct(new(Qux)))
+	return nil
+}
+
+func injectNoError() *Foo {
+	wire.Build(NewFoo, wire.PostConstruct(new(*Foo)))
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+func main() {
+	func() {
+		defer func() {
+			fmt.Println("recovered:", recover())
+		}()
+		injectRepanic()
+	}()
+	_, _, err := injectPanicError()
+	fmt.Println("injectPanicError:", err)
+	_, err = injectNoCleanup()
+	fmt.Println("injectNoCleanup:", err)
+}
+
+type Dir string
+
+func NewDir() (Dir, func()) {
+	return "tmp", func() { fmt.Println("remove dir") }
+}
+
+type Conn struct{}
+
+func Dial(dir Dir) (*Conn, func(context.Context) error, error) {
+	return &Conn{}, func(context.Context) error {
+		fmt.Println("close conn")
+		return nil
+	}, nil
+}
+
+type Server struct{}
+
+func NewServer(conn *Conn) *Server {
+	panic("listen failed")
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/wire/wireruntime"
+)
+
+func main() {
+	for _, backend := range []string{"s3", "gcs", "ftp"} {
+		app, cleanup, err := injectApp(&Config{Backend: backend})
+		if err != nil {
+			var serr *wireruntime.SelectError
+			fmt.Println(errors.As(err, &serr), err)
+			continue
+		}
+		fmt.Println(app.Storage.Name(), app.Logger.Prefix)
+		cleanup()
+	}
+}
+
+type Config struct {
+	Backend string
+}
+
+type Logger struct {
+	Prefix string
+}
+
+func NewLogger() *Logger {
+	fmt.Println("new logger")
+	return &Logger{Prefix: "app"}
+}
+
+type Storage interface {
+	Name() string
+}
+
+type S3Client struct{}
+
+func NewS3Client(cfg *Config) (*S3Client, func(), error) {
+	fmt.Println("open s3 client")
+	return &S3Client{}, func() { fmt.Println("close s3 client") }, nil
+}
+
+type S3Storage struct {
+	client *S3Client
+	log    *Logger
+}
+
+func NewS3Storage(client *S3Client, log *Logger) *S3Storage {
+	return &S3Storage{client: client, log: log}
+}
+
+func (*S3Storage) Name() string {
+	return "s3"
+}
+
+type GCSStorage struct{}
+
+func NewGCSStorage(cfg *Config) *GCSStorage {
+	return &GCSStorage{}
+}
+
+func (*GCSStorage) Name() string {
+	return "gcs"
+}
+
+func StorageBackend(cfg *Config) string {
+	return cfg.Backend
+}
+
+type App struct {
+	Storage Storage
+	Logger  *Logger
+}
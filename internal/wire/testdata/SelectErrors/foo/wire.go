@@ -0,0 +1,54 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+
+package main
+
+import (
+	"github.com/google/wire"
+)
+
+func injectNoError(cfg *Config) Storage {
+	wire.Build(wire.Select(new(Storage), StorageBackend, map[string]wire.ProviderSet{
+		"mem": wire.NewSet(NewMemStorage, wire.Bind(new(Storage), new(*MemStorage))),
+	}))
+	return nil
+}
+
+func injectNotProvided(cfg *Config) (Storage, error) {
+	wire.Build(wire.Select(new(Storage), StorageBackend, map[string]wire.ProviderSet{
+		"mem": wire.NewSet(NewMemStorage),
+	}))
+	return nil, nil
+}
+
+func injectKeyMismatch(cfg *Config) (Storage, error) {
+	wire.Build(wire.Select(new(Storage), StorageShard, map[string]wire.ProviderSet{
+		"mem": wire.NewSet(NewMemStorage, wire.Bind(new(Storage), new(*MemStorage))),
+	}))
+	return nil, nil
+}
+
+func injectNotMap(cfg *Config) (Storage, error) {
+	wire.Build(wire.Select(new(Storage), StorageBackend, wire.NewSet(NewMemStorage)))
+	return nil, nil
+}
+
+func injectMissing() (Storage, error) {
+	wire.Build(wire.Select(new(Storage), StorageBackend, map[string]wire.ProviderSet{
+		"mem": wire.NewSet(NewMemStorage, wire.Bind(new(Storage), new(*MemStorage))),
+	}))
+	return nil, nil
+}
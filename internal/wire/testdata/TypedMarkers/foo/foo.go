@@ -0,0 +1,71 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func main() {
+	fmt.Println(injectFooBar().String())
+	fmt.Println(injectPort())
+}
+
+type Fooer interface {
+	Foo() string
+}
+
+type MyFooer string
+
+func (f *MyFooer) Foo() string {
+	return string(*f)
+}
+
+func provideMyFooer() *MyFooer {
+	f := MyFooer("Hello, World!")
+	return &f
+}
+
+type Greeting string
+
+type Count int
+
+type FooBar struct {
+	Fooer  Fooer
+	Count  Count
+	Reader io.Reader
+}
+
+func (fb FooBar) String() string {
+	var sb strings.Builder
+	sb.WriteString(fb.Fooer.Foo())
+	sb.WriteString(" x")
+	fmt.Fprint(&sb, fb.Count, " ")
+	fmt.Fprint(&sb, fb.Reader.(*strings.Reader).Len())
+	return sb.String()
+}
+
+var defaultReader = strings.NewReader("abc")
+
+type Config struct {
+	Port int64
+	Host string
+}
+
+func provideConfig() *Config {
+	return &Config{Port: 8080, Host: "localhost"}
+}
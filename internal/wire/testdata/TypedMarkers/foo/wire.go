@@ -0,0 +1,42 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+
+package main
+
+import (
+	"io"
+
+	"github.com/google/wire"
+)
+
+func injectFooBar() FooBar {
+	wire.Build(
+		provideMyFooer,
+		wire.BindTo[Fooer, *MyFooer](),
+		wire.ValueOf[Count](3),
+		wire.ValueOf[io.Reader](defaultReader),
+		wire.StructOf[FooBar]("*"),
+	)
+	return FooBar{}
+}
+
+func injectPort() int64 {
+	wire.Build(
+		provideConfig,
+		wire.FieldsFrom[*Config]("Port"),
+	)
+	return 0
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build wireinject
+
+package main
+
+import (
+	"github.com/google/wire"
+)
+
+func injectBindToNonInterface() Bar {
+	// Fails because Foo is not an interface.
+	wire.Build(provideBar, wire.BindTo[Foo, Bar]())
+	return ""
+}
+
+func injectBindToNotImplemented() Fooer {
+	// Fails because Bar does not implement Fooer.
+	wire.Build(provideBar, wire.BindTo[Fooer, Bar]())
+	return nil
+}
+
+func injectStructOfNonStruct() Foo {
+	// Fails because Foo is not a struct.
+	wire.Build(wire.StructOf[Foo]("*"))
+	return ""
+}
+
+func injectStructOfMissingField() FooBar {
+	// Fails because FooBar has no field named Bar.
+	wire.Build(provideFoo, wire.StructOf[FooBar]("Bar"))
+	return FooBar{}
+}
+
+func injectFieldsFromNonStruct() Foo {
+	// Fails because Bar is not a struct.
+	wire.Build(provideBar, wire.FieldsFrom[Bar]("Foo"))
+	return ""
+}
+
+func injectFieldsFromNoFields() Foo {
+	// Fails because no fields are given.
+	wire.Build(wire.FieldsFrom[FooBar]())
+	return ""
+}
+
+func injectValueOfTooComplex() Foo {
+	// Fails because the argument calls a function.
+	wire.Build(wire.ValueOf[Foo](provideFoo()))
+	return ""
+}
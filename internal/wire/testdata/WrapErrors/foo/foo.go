@@ -0,0 +1,67 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/wire/wireruntime"
+)
+
+func main() {
+	_, err := injectApp()
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, errRefused))
+	var perr *wireruntime.ProviderError
+	if errors.As(err, &perr) {
+		fmt.Println(perr.Provider, perr.Type)
+	}
+	_, err = injectParallel()
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, errRefused))
+}
+
+var errRefused = errors.New("connection refused")
+
+type Config struct{}
+
+type DB struct{}
+
+type Cache struct{}
+
+type App struct{}
+
+type Both struct{}
+
+func NewConfig() (*Config, error) {
+	return &Config{}, nil
+}
+
+func NewDB(cfg *Config) (*DB, error) {
+	return nil, errRefused
+}
+
+func NewCache(cfg *Config) (*Cache, error) {
+	return nil, errors.New("cache unavailable")
+}
+
+func NewApp(db *DB) *App {
+	return &App{}
+}
+
+func NewBoth(db *DB, cache *Cache) *Both {
+	return &Both{}
+}
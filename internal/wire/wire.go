@@ -27,6 +27,7 @@ import (
 	"go/token"
 	"go/types"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
@@ -66,6 +67,80 @@ type GenerateOptions struct {
 	Header           []byte
 	PrefixOutputFile string
 	Tags             string
+
+	// Variants, if not empty, make Generate write one file per variant
+	// instead of a single wire_gen.go for each package.
+	Variants []Variant
+
+	// InjectorOptions are the default options for every injector. The
+	// options passed to an injector's wire.Build call are added to them.
+	InjectorOptions
+}
+
+// Variant is a set of build tags to generate a separate file for. The file
+// is named <name>_wire_gen.go and is only built with all of the tags.
+type Variant struct {
+	Name string
+	// Tags is a space-separated list of build tags. They are added to
+	// GenerateOptions.Tags when loading the packages.
+	Tags string
+}
+
+// ParseVariants parses a comma-separated list of variants, each written as
+// name:tags, like "prod:prod,dev:dev,test:fake netgo".
+func ParseVariants(s string) ([]Variant, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var variants []Variant
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(s, ",") {
+		i := strings.Index(field, ":")
+		if i == -1 {
+			return nil, fmt.Errorf("variant %q is not of the form name:tags", field)
+		}
+		v := Variant{Name: strings.TrimSpace(field[:i]), Tags: strings.Join(strings.Fields(field[i+1:]), " ")}
+		if !isVariantWord(v.Name) {
+			return nil, fmt.Errorf("variant %q has an invalid name", field)
+		}
+		if seen[v.Name] {
+			return nil, fmt.Errorf("variant %s is listed more than once", v.Name)
+		}
+		seen[v.Name] = true
+		if v.Tags == "" {
+			return nil, fmt.Errorf("variant %s has no build tags", v.Name)
+		}
+		for _, tag := range strings.Fields(v.Tags) {
+			if !isVariantWord(tag) {
+				return nil, fmt.Errorf("variant %s has invalid build tag %q", v.Name, tag)
+			}
+		}
+		variants = append(variants, v)
+	}
+	return variants, nil
+}
+
+// FormatVariants returns the list of variants in the form accepted by
+// ParseVariants.
+func FormatVariants(variants []Variant) string {
+	fields := make([]string, len(variants))
+	for i, v := range variants {
+		fields[i] = v.Name + ":" + v.Tags
+	}
+	return strings.Join(fields, ",")
+}
+
+// isVariantWord reports whether s can be used as a variant name or build tag.
+func isVariantWord(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' {
+			return false
+		}
+	}
+	return true
 }
 
 // Generate performs dependency injection for the packages that match the given
@@ -84,7 +159,32 @@ func Generate(ctx context.Context, wd string, env []string, patterns []string, o
 	if opts == nil {
 		opts = &GenerateOptions{}
 	}
-	pkgs, errs := load(ctx, wd, env, opts.Tags, patterns)
+	if len(opts.Variants) == 0 {
+		return generate(ctx, wd, env, patterns, opts, nil)
+	}
+	var generated []GenerateResult
+	for i := range opts.Variants {
+		v := &opts.Variants[i]
+		gens, errs := generate(ctx, wd, env, patterns, opts, v)
+		if len(errs) > 0 {
+			return nil, variantErrors(v, errs)
+		}
+		for j := range gens {
+			gens[j].Errs = variantErrors(v, gens[j].Errs)
+		}
+		generated = append(generated, gens...)
+	}
+	return generated, nil
+}
+
+// generate runs Generate for a single variant, or for no variant if v is nil.
+func generate(ctx context.Context, wd string, env []string, patterns []string, opts *GenerateOptions, v *Variant) ([]GenerateResult, []error) {
+	tags, fileName := opts.Tags, "wire_gen.go"
+	if v != nil {
+		tags = strings.TrimSpace(tags + " " + v.Tags)
+		fileName = v.Name + "_wire_gen.go"
+	}
+	pkgs, errs := load(ctx, wd, env, tags, patterns)
 	if len(errs) > 0 {
 		return nil, errs
 	}
@@ -96,15 +196,24 @@ func Generate(ctx context.Context, wd string, env []string, patterns []string, o
 			generated[i].Errs = append(generated[i].Errs, err)
 			continue
 		}
-		generated[i].OutputPath = filepath.Join(outDir, opts.PrefixOutputFile+"wire_gen.go")
+		generated[i].OutputPath = filepath.Join(outDir, opts.PrefixOutputFile+fileName)
+		if v != nil {
+			// A wire_gen.go from before the variants declares the same
+			// injectors as the variant files.
+			stale := filepath.Join(outDir, opts.PrefixOutputFile+"wire_gen.go")
+			if _, err := os.Stat(stale); err == nil {
+				generated[i].Errs = append(generated[i].Errs, fmt.Errorf("%s conflicts with the variant files; remove it", stale))
+				continue
+			}
+		}
 		g := newGen(pkg)
-		injectorFiles, errs := generateInjectors(g, pkg)
+		injectorFiles, errs := generateInjectors(g, pkg, opts.InjectorOptions)
 		if len(errs) > 0 {
 			generated[i].Errs = errs
 			continue
 		}
 		copyNonInjectorDecls(g, injectorFiles, pkg.TypesInfo)
-		goSrc := g.frame(opts.Tags)
+		goSrc := g.frame(opts, v)
 		if len(opts.Header) > 0 {
 			goSrc = append(opts.Header, goSrc...)
 		}
@@ -121,6 +230,13 @@ func Generate(ctx context.Context, wd string, env []string, patterns []string, o
 	return generated, nil
 }
 
+// variantErrors prefixes errs with the name of the variant they occurred in.
+func variantErrors(v *Variant, errs []error) []error {
+	return mapErrors(errs, func(err error) error {
+		return fmt.Errorf("variant %s: %v", v.Name, err)
+	})
+}
+
 func detectOutputDir(paths []string) (string, error) {
 	if len(paths) == 0 {
 		return "", errors.New("no files to derive output directory from")
@@ -134,8 +250,9 @@ func detectOutputDir(paths []string) (string, error) {
 	return dir, nil
 }
 
-// generateInjectors generates the injectors for a given package.
-func generateInjectors(g *gen, pkg *packages.Package) (injectorFiles []*ast.File, _ []error) {
+// generateInjectors generates the injectors for a given package, using opts
+// as the default options for each injector.
+func generateInjectors(g *gen, pkg *packages.Package, opts InjectorOptions) (injectorFiles []*ast.File, _ []error) {
 	oc := newObjectCache([]*packages.Package{pkg})
 	injectorFiles = make([]*ast.File, 0, len(pkg.Syntax))
 	ec := new(errorCollector)
@@ -180,7 +297,7 @@ func generateInjectors(g *gen, pkg *packages.Package) (injectorFiles []*ast.File
 				ec.add(notePositionAll(g.pkg.Fset.Position(fn.Pos()), errs)...)
 				continue
 			}
-			if errs := g.inject(fn.Pos(), fn.Name.Name, sig, set, fn.Doc); len(errs) > 0 {
+			if errs := g.inject(fn.Pos(), fn.Name.Name, sig, set, fn.Doc, opts.merge(set.Options)); len(errs) > 0 {
 				ec.add(errs...)
 				continue
 			}
@@ -246,6 +363,9 @@ type gen struct {
 	imports     map[string]importInfo
 	anonImports map[string]bool
 	values      map[ast.Expr]string
+	// proxies maps from intercepted interface type, as returned by
+	// typeString, to the name of its generated proxy type.
+	proxies map[string]string
 }
 
 func newGen(pkg *packages.Package) *gen {
@@ -254,21 +374,34 @@ func newGen(pkg *packages.Package) *gen {
 		anonImports: make(map[string]bool),
 		imports:     make(map[string]importInfo),
 		values:      make(map[ast.Expr]string),
+		proxies:     make(map[string]string),
 	}
 }
 
 // frame bakes the built up source body into an unformatted Go source file.
-func (g *gen) frame(tags string) []byte {
+// If v is not nil, the file is only built with the variant's tags.
+func (g *gen) frame(opts *GenerateOptions, v *Variant) []byte {
 	if g.buf.Len() == 0 {
 		return nil
 	}
 	var buf bytes.Buffer
-	if len(tags) > 0 {
-		tags = fmt.Sprintf(" gen -tags \"%s\"", tags)
+	var args string
+	if len(opts.Tags) > 0 {
+		args = fmt.Sprintf(" -tags \"%s\"", opts.Tags)
+	}
+	if len(opts.Variants) > 0 {
+		args += fmt.Sprintf(" -variants \"%s\"", FormatVariants(opts.Variants))
+	}
+	if len(args) > 0 {
+		args = " gen" + args
 	}
 	buf.WriteString("// Code generated by Wire. DO NOT EDIT.\n\n")
-	buf.WriteString("//go:generate go run -mod=mod github.com/google/wire/cmd/wire" + tags + "\n")
-	buf.WriteString("//+build !wireinject\n\n")
+	buf.WriteString("//go:generate go run -mod=mod github.com/google/wire/cmd/wire" + args + "\n")
+	if v == nil {
+		buf.WriteString("//+build !wireinject\n\n")
+	} else {
+		buf.WriteString("//go:build !wireinject && " + strings.Join(strings.Fields(v.Tags), " && ") + "\n\n")
+	}
 	buf.WriteString("package ")
 	buf.WriteString(g.pkg.Name)
 	buf.WriteString("\n\n")
@@ -308,14 +441,27 @@ func (g *gen) frame(tags string) []byte {
 }
 
 // inject emits the code for an injector.
-func (g *gen) inject(pos token.Pos, name string, sig *types.Signature, set *ProviderSet, doc *ast.CommentGroup) []error {
+func (g *gen) inject(pos token.Pos, name string, sig *types.Signature, set *ProviderSet, doc *ast.CommentGroup, opts InjectorOptions) []error {
 	injectSig, err := funcOutput(sig)
 	if err != nil {
 		return []error{notePosition(g.pkg.Fset.Position(pos),
 			fmt.Errorf("inject %s: %v", name, err))}
 	}
+	if set.Options.PanicsAsErrors && !injectSig.err {
+		return []error{notePosition(g.pkg.Fset.Position(pos),
+			fmt.Errorf("inject %s: wire.PanicsAsErrors requires the injector to return an error", name))}
+	}
+	if set.Options.CheckContext && !injectSig.err {
+		return []error{notePosition(g.pkg.Fset.Position(pos),
+			fmt.Errorf("inject %s: wire.CheckContext requires the injector to return an error", name))}
+	}
+	if set.Options.CheckContext && !hasContextParam(sig) {
+		return []error{notePosition(g.pkg.Fset.Position(pos),
+			fmt.Errorf("inject %s: wire.CheckContext requires the injector to take a context.Context", name))}
+	}
 	params := sig.Params()
-	calls, errs := solve(g.pkg.Fset, injectSig.out, params, set)
+	out, _ := appType(injectSig.out)
+	calls, errs := solve(g.pkg.Fset, out, params, set)
 	if len(errs) > 0 {
 		return mapErrors(errs, func(e error) error {
 			if w, ok := e.(*wireErr); ok {
@@ -326,46 +472,112 @@ func (g *gen) inject(pos token.Pos, name string, sig *types.Signature, set *Prov
 	}
 	type pendingVar struct {
 		name     string
+		typ      types.Type
 		expr     ast.Expr
 		typeInfo *types.Info
 	}
 	var pendingVars []pendingVar
+	var pendingProxies []*call
 	ec := new(errorCollector)
-	for i := range calls {
-		c := &calls[i]
-		if c.hasCleanup && !injectSig.cleanup {
-			ts := types.TypeString(c.out, nil)
-			ec.add(notePosition(
-				g.pkg.Fset.Position(pos),
-				fmt.Errorf("inject %s: provider for %s returns cleanup but injection does not return cleanup function", name, ts)))
-		}
-		if c.hasErr && !injectSig.err {
-			ts := types.TypeString(c.out, nil)
-			ec.add(notePosition(
-				g.pkg.Fset.Position(pos),
-				fmt.Errorf("inject %s: provider for %s returns error but injection not allowed to fail", name, ts)))
-		}
-		if c.kind == valueExpr {
-			if err := accessibleFrom(c.valueTypeInfo, c.valueExpr, g.pkg.PkgPath); err != nil {
-				// TODO(light): Display line number of value expression.
-				ts := types.TypeString(c.out, nil)
+	// checkCalls checks the calls of the injector, or of a closure that
+	// builds a lazy value, which has the signature injectSig and is
+	// described by what in errors.
+	var checkCalls func(calls []call, injectSig outputSignature, what string)
+	checkCalls = func(calls []call, injectSig outputSignature, what string) {
+		for i := range calls {
+			c := &calls[i]
+			if c.kind == interceptorProxy && g.proxies[typeString(c.out)] == "" {
+				g.proxies[typeString(c.out)] = typeVariableName(c.out, "", func(name string) string { return "_wire" + export(name) + "Proxy" }, g.nameInFileScope)
+				pendingProxies = append(pendingProxies, c)
+			}
+			if c.cleanup != NoCleanup && injectSig.cleanup == NoCleanup {
+				ts := typeString(c.out)
+				ec.add(notePosition(
+					g.pkg.Fset.Position(pos),
+					fmt.Errorf("inject %s: provider for %s returns cleanup but %s does not return cleanup function", name, ts, what)))
+			} else if c.cleanup > CleanupFunc && injectSig.cleanup == CleanupFunc {
+				// A func() cleanup can't report errors.
+				ts := typeString(c.out)
+				if c.autoClose {
+					ec.add(notePosition(
+						g.pkg.Fset.Position(pos),
+						fmt.Errorf("inject %s: %s is closed by wire.AutoClose, whose error can't be returned from %s cleanup of type %v", name, ts, what, injectSig.cleanup)))
+				} else {
+					ec.add(notePosition(
+						g.pkg.Fset.Position(pos),
+						fmt.Errorf("inject %s: provider for %s returns cleanup of type %v, which can't be called from %s cleanup of type %v", name, ts, c.cleanup, what, injectSig.cleanup)))
+				}
+			}
+			if c.kind == selectSwitch && !injectSig.err {
+				ts := typeString(c.out)
+				ec.add(notePosition(
+					g.pkg.Fset.Position(pos),
+					fmt.Errorf("inject %s: wire.Select for %s fails for unknown keys but injection not allowed to fail", name, ts)))
+			} else if c.hasErr && !injectSig.err {
+				ts := typeString(c.out)
+				ec.add(notePosition(
+					g.pkg.Fset.Position(pos),
+					fmt.Errorf("inject %s: provider for %s returns error but injection not allowed to fail", name, ts)))
+			} else if c.postConstruct != "" && !injectSig.err {
+				ts := typeString(c.out)
 				ec.add(notePosition(
 					g.pkg.Fset.Position(pos),
-					fmt.Errorf("inject %s: value %s can't be used: %v", name, ts, err)))
+					fmt.Errorf("inject %s: %s method of %s returns error but injection not allowed to fail", name, c.postConstruct, ts)))
 			}
-			if g.values[c.valueExpr] == "" {
-				t := c.valueTypeInfo.TypeOf(c.valueExpr)
+			if c.kind == valueExpr {
+				if err := accessibleFrom(c.valueTypeInfo, c.valueExpr, g.pkg.PkgPath); err != nil {
+					// TODO(light): Display line number of value expression.
+					ts := typeString(c.out)
+					ec.add(notePosition(
+						g.pkg.Fset.Position(pos),
+						fmt.Errorf("inject %s: value %s can't be used: %v", name, ts, err)))
+				}
+				if g.values[c.valueExpr] == "" {
+					t := c.valueTypeInfo.TypeOf(c.valueExpr)
+					if c.valueType != nil {
+						t = c.valueType
+					}
 
-				name := typeVariableName(t, "", func(name string) string { return "_wire" + export(name) + "Value" }, g.nameInFileScope)
-				g.values[c.valueExpr] = name
-				pendingVars = append(pendingVars, pendingVar{
-					name:     name,
-					expr:     c.valueExpr,
-					typeInfo: c.valueTypeInfo,
-				})
+					name := typeVariableName(t, "", func(name string) string { return "_wire" + export(name) + "Value" }, g.nameInFileScope)
+					g.values[c.valueExpr] = name
+					pendingVars = append(pendingVars, pendingVar{
+						name:     name,
+						typ:      c.valueType,
+						expr:     c.valueExpr,
+						typeInfo: c.valueTypeInfo,
+					})
+				}
+			}
+			for i, key := range c.keys {
+				if err := accessibleFrom(c.keyTypeInfos[i], key, g.pkg.PkgPath); err != nil {
+					ts := typeString(c.out)
+					ec.add(notePosition(
+						g.pkg.Fset.Position(pos),
+						fmt.Errorf("inject %s: key of %s can't be used: %v", name, ts, err)))
+				}
+			}
+			if c.kind == lazyCall {
+				subSig := outputSignature{out: c.lazyOut, err: true}
+				subWhat := typeString(c.out)
+				switch c.lazy {
+				case lazyOnce:
+					// The lazy value's cleanup is called by the enclosing
+					// cleanup function.
+					subSig.cleanup = injectSig.cleanup
+					subWhat = what
+				case lazyFuncCleanup:
+					subSig.cleanup = CleanupFunc
+				}
+				checkCalls(c.sub, subSig, subWhat)
+			}
+			if c.kind == selectSwitch {
+				for _, branch := range c.sel.calls {
+					checkCalls(branch, injectSig, what)
+				}
 			}
 		}
 	}
+	checkCalls(calls, injectSig, "injection")
 	if len(ec.errors) > 0 {
 		return ec.errors
 	}
@@ -373,26 +585,98 @@ func (g *gen) inject(pos token.Pos, name string, sig *types.Signature, set *Prov
 	// Perform one pass to collect all imports, followed by the real pass.
 	injectPass(name, sig, calls, set, doc, &injectorGen{
 		g:       g,
+		opts:    opts,
 		errVar:  disambiguate("err", g.nameInFileScope),
 		discard: true,
 	})
 	injectPass(name, sig, calls, set, doc, &injectorGen{
 		g:       g,
+		opts:    opts,
 		errVar:  disambiguate("err", g.nameInFileScope),
 		discard: false,
 	})
 	if len(pendingVars) > 0 {
 		g.p("var (\n")
 		for _, pv := range pendingVars {
-			g.p("\t%s = ", pv.name)
+			g.p("\t%s", pv.name)
+			if pv.typ != nil {
+				g.p(" %s", types.TypeString(pv.typ, g.qualifyPkg))
+			}
+			g.p(" = ")
 			g.writeAST(pv.typeInfo, pv.expr)
 			g.p("\n")
 		}
 		g.p(")\n\n")
 	}
+	for _, c := range pendingProxies {
+		g.proxyDecl(g.proxies[typeString(c.out)], c.out, c.ins[1])
+	}
 	return nil
 }
 
+// proxyDecl writes the declaration of the proxy type name, which calls the
+// wireruntime.Interceptor of type interceptor around the methods of the
+// interface type iface.
+func (g *gen) proxyDecl(name string, iface, interceptor types.Type) {
+	g.p("type %s struct {\n", name)
+	g.p("\tnext %s\n", types.TypeString(iface, g.qualifyPkg))
+	g.p("\tinterceptor %s\n", types.TypeString(interceptor, g.qualifyPkg))
+	g.p("}\n\n")
+	methodCall := g.qualifiedID("wireruntime", "github.com/google/wire/wireruntime", "MethodCall")
+	methods := iface.Underlying().(*types.Interface)
+	for i := 0; i < methods.NumMethods(); i++ {
+		m := methods.Method(i)
+		sig := m.Type().(*types.Signature)
+		var paramDecls, params, args []string
+		for j := 0; j < sig.Params().Len(); j++ {
+			p := fmt.Sprintf("p%d", j)
+			t := sig.Params().At(j).Type()
+			params = append(params, p)
+			if sig.Variadic() && j == sig.Params().Len()-1 {
+				paramDecls = append(paramDecls, fmt.Sprintf("%s ...%s", p, types.TypeString(t.(*types.Slice).Elem(), g.qualifyPkg)))
+				args = append(args, p+"...")
+			} else {
+				paramDecls = append(paramDecls, fmt.Sprintf("%s %s", p, types.TypeString(t, g.qualifyPkg)))
+				args = append(args, p)
+			}
+		}
+		var results, resultTypes []string
+		for j := 0; j < sig.Results().Len(); j++ {
+			results = append(results, fmt.Sprintf("r%d", j))
+			resultTypes = append(resultTypes, types.TypeString(sig.Results().At(j).Type(), g.qualifyPkg))
+		}
+		g.p("func (p *%s) %s(%s) ", name, m.Name(), strings.Join(paramDecls, ", "))
+		if len(results) > 1 {
+			g.p("(%s) ", strings.Join(resultTypes, ", "))
+		} else if len(results) == 1 {
+			g.p("%s ", resultTypes[0])
+		}
+		g.p("{\n")
+		g.p("\tcall := &%s{Interface: %q, Method: %q, Args: []interface{}{%s}}\n", methodCall, typeString(iface), m.Name(), strings.Join(params, ", "))
+		for j := range results {
+			g.p("\tvar %s %s\n", results[j], resultTypes[j])
+		}
+		g.p("\tp.interceptor.Intercept(call, func() {\n")
+		if len(results) == 0 {
+			g.p("\t\tp.next.%s(%s)\n", m.Name(), strings.Join(args, ", "))
+		} else {
+			g.p("\t\t%s = p.next.%s(%s)\n", strings.Join(results, ", "), m.Name(), strings.Join(args, ", "))
+			g.p("\t\tcall.Results = []interface{}{%s}\n", strings.Join(results, ", "))
+		}
+		g.p("\t})\n")
+		if len(results) > 0 {
+			// The interceptor may have replaced the results.
+			g.p("\tif len(call.Results) == %d {\n", len(results))
+			for j := range results {
+				g.p("\t\t%s, _ = call.Results[%d].(%s)\n", results[j], j, resultTypes[j])
+			}
+			g.p("\t}\n")
+			g.p("\treturn %s\n", strings.Join(results, ", "))
+		}
+		g.p("}\n\n")
+	}
+}
+
 // rewritePkgRefs rewrites any package references in an AST into references for the
 // generated package.
 func (g *gen) rewritePkgRefs(info *types.Info, node ast.Node) ast.Node {
@@ -562,6 +846,11 @@ func (g *gen) nameInFileScope(name string) bool {
 			return true
 		}
 	}
+	for _, other := range g.proxies {
+		if other == name {
+			return true
+		}
+	}
 	_, obj := g.pkg.Types.Scope().LookupParent(name, token.NoPos)
 	return obj != nil
 }
@@ -576,12 +865,38 @@ func (g *gen) p(format string, args ...interface{}) {
 
 // injectorGen is the per-injector pass generator state.
 type injectorGen struct {
-	g *gen
+	g    *gen
+	opts InjectorOptions
 
 	paramNames   []string
 	localNames   []string
 	cleanupNames []string
+	cleanupKinds []CleanupKind
 	errVar       string
+	// cleanupsVar is the name of the slice of cleanup functions to call if
+	// a provider panics, or the empty string if panics aren't recovered.
+	cleanupsVar string
+	// recovering is true if the injector recovers panics.
+	recovering bool
+	// otherNames are the other local variables of the injector, like
+	// the error variables of concurrent provider calls.
+	otherNames []string
+	// ctxParam is the name of the context parameter to check between
+	// provider function calls, or the empty string if it isn't checked.
+	ctxParam string
+	// funcCalled is true once a provider function call has been written.
+	funcCalled bool
+	// observer is the name of the wireruntime.Observer parameter, or the
+	// empty string if the injector doesn't take one.
+	observer string
+	// doneVar is the name of the function returned by wireruntime.Observe,
+	// and doneDeclared is true once it has been declared.
+	doneVar      string
+	doneDeclared bool
+	// lazyCleanup is the kind of the cleanup function that calls the
+	// cleanup functions of the wire.Lazy values built by the injector, or
+	// NoCleanup if there is none.
+	lazyCleanup CleanupKind
 
 	// discard causes ig.p and ig.writeAST to no-op. Useful to run
 	// generation for side-effects like filling in g.imports.
@@ -602,7 +917,7 @@ func injectPass(name string, sig *types.Signature, calls []call, set *ProviderSe
 			ig.p("%s\n", c.Text)
 		}
 	}
-	ig.p("func %s(", name)
+	ig.p("func %s%s(", name, ig.typeParamList(sig.TypeParams()))
 	for i := 0; i < params.Len(); i++ {
 		if i > 0 {
 			ig.p(", ")
@@ -623,45 +938,99 @@ func injectPass(name string, sig *types.Signature, calls []call, set *ProviderSe
 			ig.p("%s %s", ig.paramNames[i], types.TypeString(pi.Type(), ig.g.qualifyPkg))
 		}
 	}
+	ig.lazyCleanup = injectSig.cleanup
+	for i := 0; i < params.Len(); i++ {
+		if isObserverType(params.At(i).Type()) {
+			ig.observer = ig.paramNames[i]
+			break
+		}
+	}
+	if ig.opts.CheckContext && injectSig.err {
+		for i := 0; i < params.Len(); i++ {
+			if isContextType(params.At(i).Type()) {
+				ig.ctxParam = ig.paramNames[i]
+				break
+			}
+		}
+	}
 	outTypeString := types.TypeString(injectSig.out, ig.g.qualifyPkg)
-	switch {
-	case injectSig.cleanup && injectSig.err:
-		ig.p(") (%s, func(), error) {\n", outTypeString)
-	case injectSig.cleanup:
-		ig.p(") (%s, func()) {\n", outTypeString)
-	case injectSig.err:
-		ig.p(") (%s, error) {\n", outTypeString)
-	default:
-		ig.p(") %s {\n", outTypeString)
+	panicErr := ig.opts.PanicsAsErrors && injectSig.err
+	if panicErr {
+		// The deferred recovery sets the error result.
+		if injectSig.cleanup != NoCleanup {
+			ig.p(") (_ %s, _ %s, %s error) {\n", outTypeString, ig.cleanupFuncType(injectSig.cleanup, ""), ig.errVar)
+		} else {
+			ig.p(") (_ %s, %s error) {\n", outTypeString, ig.errVar)
+		}
+	} else {
+		switch {
+		case injectSig.cleanup != NoCleanup && injectSig.err:
+			ig.p(") (%s, %s, error) {\n", outTypeString, ig.cleanupFuncType(injectSig.cleanup, ""))
+		case injectSig.cleanup != NoCleanup:
+			ig.p(") (%s, %s) {\n", outTypeString, ig.cleanupFuncType(injectSig.cleanup, ""))
+		case injectSig.err:
+			ig.p(") (%s, error) {\n", outTypeString)
+		default:
+			ig.p(") %s {\n", outTypeString)
+		}
 	}
+	hasCleanups := false
 	for i := range calls {
-		c := &calls[i]
-		lname := typeVariableName(c.out, "v", unexport, ig.nameInInjector)
-		ig.localNames = append(ig.localNames, lname)
-		switch c.kind {
-		case structProvider:
-			ig.structProviderCall(lname, c)
-		case funcProviderCall:
-			ig.funcProviderCall(lname, c, injectSig)
-		case valueExpr:
-			ig.valueExpr(lname, c)
-		case selectorExpr:
-			ig.fieldExpr(lname, c)
-		default:
-			panic("unknown kind")
+		if calls[i].cleanup != NoCleanup {
+			hasCleanups = true
 		}
 	}
-	if len(calls) == 0 {
-		ig.p("\treturn %s", ig.paramNames[set.For(injectSig.out).Arg().Index])
+	if panicErr || (hasCleanups && (ig.opts.RecoverPanics || ig.opts.PanicsAsErrors)) {
+		ig.recoverPanic(name, hasCleanups, panicErr)
+	}
+	if ig.opts.Parallel {
+		ig.parallelCalls(calls, injectSig)
 	} else {
-		ig.p("\treturn %s", ig.localNames[len(calls)-1])
+		for i := range calls {
+			c := &calls[i]
+			lname := ig.localName(c)
+			ig.localNames = append(ig.localNames, lname)
+			ig.call(lname, c, injectSig)
+		}
 	}
-	if injectSig.cleanup {
-		ig.p(", func() {\n")
-		for i := len(ig.cleanupNames) - 1; i >= 0; i-- {
-			ig.p("\t\t%s()\n", ig.cleanupNames[i])
+	out, hook := appType(injectSig.out)
+	var value string
+	if len(calls) == 0 {
+		value = ig.paramNames[set.For(out).Arg().Index]
+	} else {
+		value = ig.localNames[len(calls)-1]
+	}
+	if hook != nil {
+		// Start the values in the order they were provided. A value that
+		// is replaced by a decorator or an interceptor proxy that is also
+		// a hook is only started through the value that replaces it.
+		isHook := func(c *call) bool {
+			t, _ := unqualify(c.out)
+			return types.Implements(t, hook)
 		}
-		ig.p("\t}")
+		replaced := make(map[int]bool)
+		for j := range calls {
+			c := &calls[j]
+			if len(c.args) == 0 || c.args[0] < len(ig.paramNames) || !isHook(c) {
+				continue
+			}
+			i := c.args[0] - len(ig.paramNames)
+			if c.kind == interceptorProxy || c.kind == funcProviderCall && types.Identical(calls[i].out, c.out) {
+				replaced[i] = true
+			}
+		}
+		args := []string{value}
+		for i := range calls {
+			if !replaced[i] && isHook(&calls[i]) {
+				args = append(args, ig.localNames[i])
+			}
+		}
+		value = fmt.Sprintf("%s(%s)", ig.g.qualifiedID("wireruntime", "github.com/google/wire/wireruntime", "NewApp"), strings.Join(args, ", "))
+	}
+	ig.p("\treturn %s", value)
+	if injectSig.cleanup != NoCleanup {
+		ig.p(", ")
+		ig.cleanupFunc(injectSig.cleanup)
 	}
 	if injectSig.err {
 		ig.p(", nil")
@@ -669,19 +1038,600 @@ func injectPass(name string, sig *types.Signature, calls []call, set *ProviderSe
 	ig.p("\n}\n\n")
 }
 
-func (ig *injectorGen) funcProviderCall(lname string, c *call, injectSig outputSignature) {
-	ig.p("\t%s", lname)
-	prevCleanup := len(ig.cleanupNames)
-	if c.hasCleanup {
-		cname := disambiguate("cleanup", ig.nameInInjector)
-		ig.cleanupNames = append(ig.cleanupNames, cname)
-		ig.p(", %s", cname)
+// cleanupFunc writes a function literal of cleanup kind k that calls the
+// cleanup functions of the values provided so far in reverse order.
+func (ig *injectorGen) cleanupFunc(k CleanupKind) {
+	ctx := ""
+	if k == CleanupFuncCtxErr {
+		ctx = disambiguate("ctx", ig.nameInInjector)
+		ig.p("%s {\n", ig.cleanupFuncType(k, ctx))
+	} else {
+		ig.p("%s {\n", ig.cleanupFuncType(k, ""))
+		ctx = ig.backgroundContext(len(ig.cleanupNames))
 	}
-	if c.hasErr {
+	switch {
+	case k == CleanupFunc:
+		ig.cleanupCalls(len(ig.cleanupNames), ctx, "", "")
+	case ig.cleanupErrs(len(ig.cleanupNames)):
+		ig.p("\t\tvar errs []error\n")
+		ig.cleanupCalls(len(ig.cleanupNames), ctx, "err", "errs = append(errs, err)")
+		ig.p("\t\treturn %s(errs...)\n", ig.joinErrors())
+	default:
+		ig.cleanupCalls(len(ig.cleanupNames), ctx, "", "")
+		ig.p("\t\treturn nil\n")
+	}
+	ig.p("\t}")
+}
+
+// localName returns a new name for the local variable that holds the value
+// of c. The closures of lazy calls are named after the values they build.
+func (ig *injectorGen) localName(c *call) string {
+	switch c.kind {
+	case lazyCall:
+		prefix := "new"
+		if c.lazy == lazyOnce {
+			prefix = "lazy"
+		}
+		return typeVariableName(c.lazyOut, "v", func(name string) string { return prefix + export(name) }, ig.nameInInjector)
+	case factoryFunc:
+		return typeVariableName(c.factory.Provider.Out[0], "v", func(name string) string { return "new" + export(name) }, ig.nameInInjector)
+	default:
+		return typeVariableName(c.out, "v", unexport, ig.nameInInjector)
+	}
+}
+
+// lazyCall writes the closure that builds the value of the lazy call c.
+// The closure captures the injector's arguments and the values built
+// before it, and builds the rest.
+func (ig *injectorGen) lazyCall(lname string, c *call, injectSig outputSignature) {
+	captured := ig.localNames[:c.subBase-len(ig.paramNames)]
+	sub := &injectorGen{
+		g:          ig.g,
+		opts:       InjectorOptions{WrapErrors: ig.opts.WrapErrors},
+		paramNames: append(ig.paramNames[:len(ig.paramNames):len(ig.paramNames)], captured...),
+		errVar:     ig.errVar,
+		observer:   ig.observer,
+		discard:    ig.discard,
+	}
+	switch c.lazy {
+	case lazyOnce:
+		sub.lazyCleanup = ig.lazyCleanup
+	case lazyFuncCleanup:
+		sub.lazyCleanup = CleanupFunc
+	}
+	subSig := outputSignature{out: c.lazyOut, err: true}
+	outType := types.TypeString(c.lazyOut, ig.g.qualifyPkg)
+	// cleanupVar holds the wireruntime.LazyCleanup of a wire.Lazy value.
+	cleanupVar := ""
+	hasCleanups := false
+	for i := range c.sub {
+		if c.sub[i].cleanup != NoCleanup || c.sub[i].kind == lazyCall && c.sub[i].lazy == lazyOnce {
+			hasCleanups = true
+		}
+	}
+	switch c.lazy {
+	case lazyOnce:
+		if hasCleanups && ig.lazyCleanup != NoCleanup {
+			cleanupVar = disambiguate("cleanup", ig.nameInInjector)
+			ig.p("\t%s := %s(", cleanupVar, ig.g.qualifiedID("wireruntime", "github.com/google/wire/wireruntime", "NewLazyCleanup"))
+			ig.noopCleanupFunc()
+			ig.p(")\n")
+			ig.otherNames = append(ig.otherNames, cleanupVar)
+			ig.cleanupNames = append(ig.cleanupNames, cleanupVar+".Take()")
+			ig.cleanupKinds = append(ig.cleanupKinds, ig.lazyCleanup)
+			sub.otherNames = append(sub.otherNames, cleanupVar)
+		}
+		ig.p("\t%s := %s(func() (%s, error) {\n", lname, ig.g.qualifiedID("wireruntime", "github.com/google/wire/wireruntime", "NewLazy"), outType)
+	case lazyFunc:
+		ig.p("\t%s := func() (%s, error) {\n", lname, outType)
+	case lazyFuncCleanup:
+		subSig.cleanup = CleanupFunc
+		ig.p("\t%s := func() (%s, func(), error) {\n", lname, outType)
+	}
+	for i := range c.sub {
+		sc := &c.sub[i]
+		slname := sub.localName(sc)
+		sub.localNames = append(sub.localNames, slname)
+		sub.call(slname, sc, subSig)
+	}
+	var value string
+	if c.subOut < len(sub.paramNames) {
+		value = sub.paramNames[c.subOut]
+	} else {
+		value = sub.localNames[c.subOut-len(sub.paramNames)]
+	}
+	switch {
+	case cleanupVar != "":
+		// The injector's cleanup function may already have taken the
+		// cleanup function, in which case the values are cleaned up
+		// right away.
+		cleanupFunc := disambiguate("cleanup", sub.nameInInjector)
+		ig.p("\t%s := ", cleanupFunc)
+		sub.cleanupFunc(ig.lazyCleanup)
+		ig.p("\n")
+		ig.p("\tif !%s.Set(%s) {\n", cleanupVar, cleanupFunc)
+		ctx := ""
+		if ig.lazyCleanup == CleanupFuncCtxErr {
+			ctx = ig.g.qualifiedID("context", "context", "Background") + "()"
+		}
+		ig.p("\t\t%s(%s)\n", cleanupFunc, ctx)
+		ig.p("\t\treturn %s, %s\n", zeroValue(c.lazyOut, ig.g.qualifyPkg), ig.g.qualifiedID("wireruntime", "github.com/google/wire/wireruntime", "ErrCleanedUp"))
+		ig.p("\t}\n")
+		ig.p("\treturn %s, nil\n", value)
+	case c.lazy == lazyFuncCleanup:
+		ig.p("\treturn %s, ", value)
+		sub.cleanupFunc(CleanupFunc)
+		ig.p(", nil\n")
+	default:
+		ig.p("\treturn %s, nil\n", value)
+	}
+	if c.lazy == lazyOnce {
+		ig.p("\t})\n")
+	} else {
+		ig.p("\t}\n")
+	}
+}
+
+// factoryFunc writes the function literal of the factory call c, which
+// calls the provider function with the literal's arguments and the values
+// of c.args. The literal's local variables are added to ig.otherNames, so
+// that they don't shadow the injector's.
+func (ig *injectorGen) factoryFunc(lname string, c *call) {
+	fa := c.factory
+	p := fa.Provider
+	fnOut, err := funcOutput(fa.Sig)
+	if err != nil {
+		// This should be checked by processFactory already.
+		panic(err)
+	}
+	params := fa.Sig.Params()
+	paramNames := make([]string, params.Len())
+	for i := range paramNames {
+		pi := params.At(i)
+		a := pi.Name()
+		if a == "" || a == "_" {
+			a = typeVariableName(pi.Type(), "arg", unexport, ig.nameInInjector)
+		} else {
+			a = disambiguate(a, ig.nameInInjector)
+		}
+		paramNames[i] = a
+		ig.otherNames = append(ig.otherNames, a)
+	}
+
+	ig.p("\t%s := ", lname)
+	t, _ := unqualify(c.out)
+	named := !types.Identical(t, fa.Sig)
+	if named {
+		// Convert the literal to the named function type.
+		ig.p("%s(", types.TypeString(t, ig.g.qualifyPkg))
+	}
+	ig.p("func(")
+	for i, a := range paramNames {
+		if i > 0 {
+			ig.p(", ")
+		}
+		ig.p("%s %s", a, types.TypeString(params.At(i).Type(), ig.g.qualifyPkg))
+	}
+	outTypeString := types.TypeString(fnOut.out, ig.g.qualifyPkg)
+	switch {
+	case fnOut.cleanup != NoCleanup && fnOut.err:
+		ig.p(") (%s, %s, error) {\n", outTypeString, ig.cleanupFuncType(fnOut.cleanup, ""))
+	case fnOut.cleanup != NoCleanup:
+		ig.p(") (%s, %s) {\n", outTypeString, ig.cleanupFuncType(fnOut.cleanup, ""))
+	case fnOut.err:
+		ig.p(") (%s, error) {\n", outTypeString)
+	default:
+		ig.p(") %s {\n", outTypeString)
+	}
+
+	// inner is the provider function call in the literal.
+	inner := &call{
+		kind: funcProviderCall,
+		pkg:  p.Pkg,
+		name: p.Name,
+		out:  p.Out[0],
+	}
+	vname := typeVariableName(p.Out[0], "v", unexport, ig.nameInInjector)
+	ig.otherNames = append(ig.otherNames, vname)
+	cleanup := ""
+	ig.observeStart(inner, true)
+	ig.p("\t%s", vname)
+	if p.AutoClose {
+		cleanup = vname + ".Close"
+	} else if p.HasCleanup {
+		cleanup = disambiguate("cleanup", ig.nameInInjector)
+		ig.otherNames = append(ig.otherNames, cleanup)
+		ig.p(", %s", cleanup)
+	}
+	if p.HasErr {
 		ig.p(", %s", ig.errVar)
 	}
-	ig.p(" := ")
-	ig.p("%s(", ig.g.qualifiedID(c.pkg.Name(), c.pkg.Path(), c.name))
+	ig.p(" := %s%s(", ig.g.qualifiedID(p.Pkg.Name(), p.Pkg.Path(), p.Name), ig.typeArgList(p.TypeArgs))
+	next := 0
+	for i, param := range fa.Params {
+		if i > 0 {
+			ig.p(", ")
+		}
+		if param != -1 {
+			ig.p("%s", paramNames[param])
+			continue
+		}
+		if a := c.args[next]; a < len(ig.paramNames) {
+			ig.p("%s", ig.paramNames[a])
+		} else {
+			ig.p("%s", ig.localNames[a-len(ig.paramNames)])
+		}
+		next++
+	}
+	if p.Varargs {
+		ig.p("...")
+	}
+	ig.p(")\n")
+	if p.HasErr {
+		ig.observeFinish(ig.errVar)
+		ig.p("\tif %s != nil {\n", ig.errVar)
+		ig.wrapError(inner, ig.errVar)
+		ig.p("\t\treturn %s", zeroValue(fnOut.out, ig.g.qualifyPkg))
+		if fnOut.cleanup != NoCleanup {
+			ig.p(", nil")
+		}
+		ig.p(", %s\n", ig.errVar)
+		ig.p("\t}\n")
+	} else {
+		ig.observeFinish("")
+	}
+	ig.p("\treturn %s", vname)
+	if cleanup != "" {
+		ig.p(", %s", cleanup)
+	}
+	if fnOut.err {
+		ig.p(", nil")
+	}
+	ig.p("\n\t}")
+	if named {
+		ig.p(")")
+	}
+	ig.p("\n")
+}
+
+// noopCleanup declares a variable that holds a cleanup function of kind
+// ig.lazyCleanup that does nothing, and returns its name.
+func (ig *injectorGen) noopCleanup() string {
+	name := disambiguate("cleanup", ig.nameInInjector)
+	ig.p("\t%s := ", name)
+	ig.noopCleanupFunc()
+	ig.p("\n")
+	return name
+}
+
+// noopCleanupFunc writes a function literal of cleanup kind ig.lazyCleanup
+// that does nothing.
+func (ig *injectorGen) noopCleanupFunc() {
+	if ig.lazyCleanup == CleanupFunc {
+		ig.p("func() {}")
+		return
+	}
+	ig.p("%s {\n", ig.cleanupFuncType(ig.lazyCleanup, "_"))
+	ig.p("\t\treturn nil\n")
+	ig.p("\t}")
+}
+
+// selectSwitch writes a switch statement on the key of the select call c,
+// whose cases build the values of the branches and assign the provided one
+// to lname. The values that the branches need from outside are the last
+// values of c.args. The cleanup functions of a branch's values are called
+// by a single cleanup function of kind ig.lazyCleanup.
+func (ig *injectorGen) selectSwitch(lname string, c *call, injectSig outputSignature) {
+	sel := c.sel
+	outType, _ := unqualify(c.out)
+	ig.p("\tvar %s %s\n", lname, types.TypeString(outType, ig.g.qualifyPkg))
+	hasCleanups := false
+	for _, branch := range sel.calls {
+		for i := range branch {
+			if branch[i].cleanup != NoCleanup {
+				hasCleanups = true
+			}
+		}
+	}
+	cleanupVar := ""
+	if hasCleanups && ig.lazyCleanup != NoCleanup {
+		cleanupVar = ig.noopCleanup()
+		ig.otherNames = append(ig.otherNames, cleanupVar)
+	}
+	argName := func(a int) string {
+		if a < len(ig.paramNames) {
+			return ig.paramNames[a]
+		}
+		return ig.localNames[a-len(ig.paramNames)]
+	}
+	keyVar := disambiguate("key", ig.nameInInjector)
+	ig.otherNames = append(ig.otherNames, keyVar)
+	ig.p("\tswitch %s := %s(", keyVar, ig.g.qualifiedID(sel.Key.Pkg.Name(), sel.Key.Pkg.Path(), sel.Key.Name))
+	for i := range sel.Key.Args {
+		if i > 0 {
+			ig.p(", ")
+		}
+		ig.p("%s", argName(c.args[i]))
+	}
+	ig.p("); %s {\n", keyVar)
+
+	// The branches' values are named like the injector's, with the values
+	// they need from outside as their givens.
+	free := make([]string, len(sel.Free))
+	for i, a := range sel.freeArgs {
+		free[i] = argName(c.args[a])
+	}
+	paramNames, localNames, otherNames, doneDeclared := ig.paramNames, ig.localNames, ig.otherNames, ig.doneDeclared
+	ig.otherNames = append(append(otherNames[:len(otherNames):len(otherNames)], paramNames...), localNames...)
+	prevCleanup := len(ig.cleanupNames)
+	for i, key := range sel.keys {
+		ig.paramNames, ig.localNames, ig.doneDeclared = free, nil, doneDeclared
+		ig.p("\tcase ")
+		ig.writeAST(sel.keyInfo, key)
+		ig.p(":\n")
+		for j := range sel.calls[i] {
+			bc := &sel.calls[i][j]
+			bname := ig.localName(bc)
+			ig.localNames = append(ig.localNames, bname)
+			ig.call(bname, bc, injectSig)
+		}
+		ig.p("\t%s = %s\n", lname, argName(sel.outs[i]))
+		if cleanupVar != "" && len(ig.cleanupNames) > prevCleanup {
+			names, kinds := ig.cleanupNames, ig.cleanupKinds
+			ig.cleanupNames, ig.cleanupKinds = names[prevCleanup:], kinds[prevCleanup:]
+			ig.p("\t%s = ", cleanupVar)
+			ig.cleanupFunc(ig.lazyCleanup)
+			ig.p("\n")
+			ig.cleanupNames, ig.cleanupKinds = names, kinds
+		}
+		ig.cleanupNames, ig.cleanupKinds = ig.cleanupNames[:prevCleanup], ig.cleanupKinds[:prevCleanup]
+	}
+	ig.paramNames, ig.localNames, ig.otherNames, ig.doneDeclared = paramNames, localNames, otherNames, doneDeclared
+	if ig.doneVar != "" {
+		// The branches may have named the wireruntime.Observe function.
+		ig.otherNames = append(ig.otherNames, ig.doneVar)
+	}
+	ig.p("\tdefault:\n")
+	ig.p("\t\tvar %s error = &%s{Type: %q, Key: %s}\n", ig.errVar, ig.g.qualifiedID("wireruntime", "github.com/google/wire/wireruntime", "SelectError"), typeString(c.out), keyVar)
+	ig.failReturn(len(ig.cleanupNames), injectSig)
+	ig.p("\t}\n")
+	if cleanupVar != "" {
+		ig.cleanupNames = append(ig.cleanupNames, cleanupVar)
+		ig.cleanupKinds = append(ig.cleanupKinds, ig.lazyCleanup)
+	}
+}
+
+// call writes the statement that assigns the result of c to lname.
+func (ig *injectorGen) call(lname string, c *call, injectSig outputSignature) {
+	switch c.kind {
+	case structProvider:
+		ig.structProviderCall(lname, c)
+		ig.postConstruct(lname, c, injectSig)
+	case funcProviderCall:
+		ig.funcProviderCall(lname, c, injectSig)
+	case valueExpr:
+		ig.valueExpr(lname, c)
+	case selectorExpr:
+		ig.fieldExpr(lname, c)
+	case multibindingLit:
+		ig.multibindingLit(lname, c)
+	case interceptorProxy:
+		ig.interceptorProxy(lname, c)
+	case lazyCall:
+		ig.lazyCall(lname, c, injectSig)
+	case factoryFunc:
+		ig.factoryFunc(lname, c)
+	case zeroVar:
+		ig.p("\tvar %s %s\n", lname, types.TypeString(c.out, ig.g.qualifyPkg))
+	case selectSwitch:
+		ig.selectSwitch(lname, c, injectSig)
+	default:
+		panic("unknown kind")
+	}
+}
+
+// parallelCalls writes the calls in waves. The provider functions in a wave
+// depend only on values from earlier waves, so they are called concurrently.
+func (ig *injectorGen) parallelCalls(calls []call, injectSig outputSignature) {
+	// Name every value first, since they aren't written in order.
+	for i := range calls {
+		ig.localNames = append(ig.localNames, ig.localName(&calls[i]))
+	}
+	// wave[i] is the wave after which the value of calls[i] is available.
+	// Values that don't depend on any provider function are in wave 0.
+	wave := make([]int, len(calls))
+	lastWave := 0
+	for i := range calls {
+		c := &calls[i]
+		for _, a := range c.args {
+			if a >= len(ig.paramNames) && wave[a-len(ig.paramNames)] > wave[i] {
+				wave[i] = wave[a-len(ig.paramNames)]
+			}
+		}
+		if c.kind == funcProviderCall {
+			wave[i]++
+		}
+		if wave[i] > lastWave {
+			lastWave = wave[i]
+		}
+	}
+	for w := 0; w <= lastWave; w++ {
+		var funcCalls []int
+		for i := range calls {
+			if wave[i] == w && calls[i].kind == funcProviderCall {
+				funcCalls = append(funcCalls, i)
+			}
+		}
+		if len(funcCalls) == 1 {
+			i := funcCalls[0]
+			ig.funcProviderCall(ig.localNames[i], &calls[i], injectSig)
+		} else if len(funcCalls) > 1 {
+			ig.concurrentCalls(funcCalls, calls, injectSig)
+		}
+		for i := range calls {
+			if wave[i] == w && calls[i].kind != funcProviderCall {
+				ig.call(ig.localNames[i], &calls[i], injectSig)
+			}
+		}
+	}
+}
+
+// concurrentCalls writes the provider function calls at the given indices
+// of calls, which don't depend on each other, in separate goroutines. If any
+// of them fail, the cleanup functions of the others and of all the earlier
+// calls are called before returning the errors.
+func (ig *injectorGen) concurrentCalls(indices []int, calls []call, injectSig outputSignature) {
+	ig.checkContext(injectSig)
+	prevCleanup := len(ig.cleanupNames)
+	cleanupNames := make([]string, len(indices))
+	errNames := make([]string, len(indices))
+	ig.p("\tvar (\n")
+	for j, i := range indices {
+		c := &calls[i]
+		lname := ig.localNames[i]
+		out, _ := unqualify(c.out)
+		ig.p("\t\t%s %s\n", lname, types.TypeString(out, ig.g.qualifyPkg))
+		if c.autoClose {
+			ig.cleanupNames = append(ig.cleanupNames, lname+".Close")
+			ig.cleanupKinds = append(ig.cleanupKinds, c.cleanup)
+		} else if c.cleanup != NoCleanup {
+			cleanupNames[j] = disambiguate("cleanup", ig.nameInInjector)
+			ig.cleanupNames = append(ig.cleanupNames, cleanupNames[j])
+			ig.cleanupKinds = append(ig.cleanupKinds, c.cleanup)
+			ig.p("\t\t%s %s\n", cleanupNames[j], ig.cleanupFuncType(c.cleanup, ""))
+		}
+		if c.hasErr {
+			errNames[j] = disambiguate(ig.errVar, ig.nameInInjector)
+			ig.otherNames = append(ig.otherNames, errNames[j])
+			ig.p("\t\t%s error\n", errNames[j])
+		}
+	}
+	// Panics in the goroutines are recovered and raised again by the
+	// injector's goroutine, where the injector's deferred function can
+	// recover them.
+	panics := ""
+	if ig.recovering {
+		panics = disambiguate("panics", ig.nameInInjector)
+		ig.otherNames = append(ig.otherNames, panics)
+		ig.p("\t\t%s [%d]interface{}\n", panics, len(indices))
+	}
+	ig.p("\t)\n")
+	wg := disambiguate("wg", ig.nameInInjector)
+	ig.otherNames = append(ig.otherNames, wg)
+	ig.p("\tvar %s %s\n", wg, ig.g.qualifiedID("sync", "sync", "WaitGroup"))
+	ig.p("\t%s.Add(%d)\n", wg, len(indices))
+	for j, i := range indices {
+		ig.p("\tgo func() {\n")
+		ig.p("\t\tdefer %s.Done()\n", wg)
+		if panics != "" {
+			ig.p("\t\tdefer func() {\n")
+			ig.p("\t\t\t%s[%d] = recover()\n", panics, j)
+			ig.p("\t\t}()\n")
+		}
+		ig.observeStart(&calls[i], true)
+		ig.p("\t\t%s", ig.localNames[i])
+		if cleanupNames[j] != "" {
+			ig.p(", %s", cleanupNames[j])
+		}
+		if errNames[j] != "" {
+			ig.p(", %s", errNames[j])
+		}
+		ig.p(" = ")
+		ig.funcCall(&calls[i])
+		ig.observeFinish(errNames[j])
+		if ig.opts.WrapErrors && errNames[j] != "" {
+			ig.p("\t\tif %s != nil {\n", errNames[j])
+			ig.wrapError(&calls[i], errNames[j])
+			ig.p("\t\t}\n")
+		}
+		ig.p("\t}()\n")
+	}
+	ig.p("\t%s.Wait()\n", wg)
+	if panics != "" {
+		ig.p("\tfor _, r := range %s {\n", panics)
+		ig.p("\t\tif r != nil {\n")
+		if ig.cleanupsVar != "" {
+			// Let the injector's deferred function clean up the values
+			// from this wave whose providers returned.
+			k := prevCleanup
+			for j, i := range indices {
+				if calls[i].cleanup == NoCleanup {
+					continue
+				}
+				cond := fmt.Sprintf("%s[%d] == nil", panics, j)
+				if errNames[j] != "" {
+					cond += fmt.Sprintf(" && %s == nil", errNames[j])
+				}
+				ig.p("\t\t\tif %s {\n", cond)
+				ig.deferCleanup(k)
+				ig.p("\t\t\t}\n")
+				k++
+			}
+		}
+		ig.p("\t\t\tpanic(r)\n")
+		ig.p("\t\t}\n")
+		ig.p("\t}\n")
+	}
+
+	var failed []string
+	for _, e := range errNames {
+		if e != "" {
+			failed = append(failed, e)
+		}
+	}
+	if len(failed) > 0 {
+		if len(failed) == 1 {
+			ig.p("\tif %s := %s; %s != nil {\n", ig.errVar, failed[0], ig.errVar)
+		} else {
+			ig.p("\tif %s := %s(%s); %s != nil {\n", ig.errVar, ig.joinErrors(), strings.Join(failed, ", "), ig.errVar)
+		}
+		// Clean up the values from this wave whose providers succeeded,
+		// then the values from earlier waves.
+		ctx := ig.backgroundContext(len(ig.cleanupNames))
+		cerr, joinErr := "", ""
+		if ig.cleanupErrs(len(ig.cleanupNames)) {
+			cerr = "cerr"
+			joinErr = fmt.Sprintf("%s = %s(%s, cerr)", ig.errVar, ig.joinErrors(), ig.errVar)
+		}
+		for j := len(indices) - 1; j >= 0; j-- {
+			c := &calls[indices[j]]
+			if c.cleanup == NoCleanup {
+				continue
+			}
+			// Cleanup functions are added in the order of indices.
+			k := prevCleanup
+			for _, i := range indices[:j] {
+				if calls[i].cleanup != NoCleanup {
+					k++
+				}
+			}
+			if errNames[j] != "" {
+				ig.p("\t\tif %s == nil {\n", errNames[j])
+				ig.cleanupCall(k, ctx, cerr, joinErr)
+				ig.p("\t\t}\n")
+			} else {
+				ig.cleanupCall(k, ctx, cerr, joinErr)
+			}
+		}
+		ig.failReturn(prevCleanup, injectSig)
+		ig.p("\t}\n")
+	}
+	if ig.cleanupsVar != "" {
+		for k := prevCleanup; k < len(ig.cleanupNames); k++ {
+			ig.deferCleanup(k)
+		}
+	}
+	// Post-construct methods are called once the whole wave has been
+	// provided, so that a failure cleans up all of its values.
+	for _, i := range indices {
+		ig.postConstruct(ig.localNames[i], &calls[i], injectSig)
+	}
+}
+
+// funcCall writes a call to the provider function of c, followed by a
+// newline.
+func (ig *injectorGen) funcCall(c *call) {
+	ig.p("%s%s(", ig.g.qualifiedID(c.pkg.Name(), c.pkg.Path(), c.name), ig.typeArgList(c.typeArgs))
 	for i, a := range c.args {
 		if i > 0 {
 			ig.p(", ")
@@ -696,28 +1646,258 @@ func (ig *injectorGen) funcProviderCall(lname string, c *call, injectSig outputS
 		ig.p("...")
 	}
 	ig.p(")\n")
+}
+
+// joinErrors returns the name of the function that joins errors in the
+// generated code. errors.Join isn't used, since it was added in Go 1.20.
+func (ig *injectorGen) joinErrors() string {
+	return ig.g.qualifiedID("wireruntime", "github.com/google/wire/wireruntime", "JoinErrors")
+}
+
+// failReturn writes calls to the first n cleanup functions, joining their
+// errors to the error variable, and then returns the error variable.
+func (ig *injectorGen) failReturn(n int, injectSig outputSignature) {
+	if ig.cleanupErrs(n) {
+		// There is no caller's context to clean up with yet.
+		ctx := ig.backgroundContext(n)
+		ig.cleanupCalls(n, ctx, "cerr", fmt.Sprintf("%s = %s(%s, cerr)", ig.errVar, ig.joinErrors(), ig.errVar))
+	} else {
+		ig.cleanupCalls(n, "", "", "")
+	}
+	ig.p("\t\treturn %s", zeroValue(injectSig.out, ig.g.qualifyPkg))
+	if injectSig.cleanup != NoCleanup {
+		ig.p(", nil")
+	}
+	ig.p(", %s\n", ig.errVar)
+}
+
+// checkContext writes a check that the injector's context isn't done before
+// a provider function call, unless it is the injector's first provider
+// function call or the injector doesn't check its context.
+func (ig *injectorGen) checkContext(injectSig outputSignature) {
+	if ig.ctxParam == "" {
+		return
+	}
+	if !ig.funcCalled {
+		ig.funcCalled = true
+		return
+	}
+	ig.p("\tif %s := %s.Err(); %s != nil {\n", ig.errVar, ig.ctxParam, ig.errVar)
+	ig.failReturn(len(ig.cleanupNames), injectSig)
+	ig.p("\t}\n")
+}
+
+// observeStart writes a call to wireruntime.Observe for the provider
+// function call c, if the injector takes a wireruntime.Observer. inClosure
+// must be true if the call is in a function literal.
+func (ig *injectorGen) observeStart(c *call, inClosure bool) {
+	if ig.observer == "" {
+		return
+	}
+	if ig.doneVar == "" {
+		ig.doneVar = disambiguate("done", ig.nameInInjector)
+		ig.otherNames = append(ig.otherNames, ig.doneVar)
+	}
+	op := ":="
+	if !inClosure {
+		if ig.doneDeclared {
+			op = "="
+		}
+		ig.doneDeclared = true
+	}
+	name := c.pkg.Path() + "." + c.name
+	ig.p("\t%s %s %s(%s, %q, %q)\n", ig.doneVar, op, ig.g.qualifiedID("wireruntime", "github.com/google/wire/wireruntime", "Observe"), ig.observer, name, typeString(c.out))
+}
+
+// observeFinish writes a call to the function returned by
+// wireruntime.Observe with the error variable errVar, which is empty if the
+// provider function doesn't return an error.
+func (ig *injectorGen) observeFinish(errVar string) {
+	if ig.observer == "" {
+		return
+	}
+	if errVar == "" {
+		errVar = "nil"
+	}
+	ig.p("\t%s(%s)\n", ig.doneVar, errVar)
+}
+
+// wrapError writes an assignment that wraps the error in errVar, returned
+// by the provider function call c, in a *wireruntime.ProviderError if the
+// WrapErrors option is set.
+func (ig *injectorGen) wrapError(c *call, errVar string) {
+	if !ig.opts.WrapErrors {
+		return
+	}
+	name := c.pkg.Path() + "." + c.name
+	ig.p("\t\t%s = &%s{Provider: %q, Type: %q, Err: %s}\n", errVar, ig.g.qualifiedID("wireruntime", "github.com/google/wire/wireruntime", "ProviderError"), name, typeString(c.out), errVar)
+}
+
+func (ig *injectorGen) funcProviderCall(lname string, c *call, injectSig outputSignature) {
+	ig.checkContext(injectSig)
+	ig.observeStart(c, false)
+	ig.p("\t%s", lname)
+	prevCleanup := len(ig.cleanupNames)
+	if c.autoClose {
+		ig.cleanupNames = append(ig.cleanupNames, lname+".Close")
+		ig.cleanupKinds = append(ig.cleanupKinds, c.cleanup)
+	} else if c.cleanup != NoCleanup {
+		cname := disambiguate("cleanup", ig.nameInInjector)
+		ig.cleanupNames = append(ig.cleanupNames, cname)
+		ig.cleanupKinds = append(ig.cleanupKinds, c.cleanup)
+		ig.p(", %s", cname)
+	}
+	if c.hasErr {
+		ig.p(", %s", ig.errVar)
+	}
+	ig.p(" := ")
+	ig.funcCall(c)
+	if c.hasErr {
+		ig.observeFinish(ig.errVar)
+	} else {
+		ig.observeFinish("")
+	}
 	if c.hasErr {
 		ig.p("\tif %s != nil {\n", ig.errVar)
-		for i := prevCleanup - 1; i >= 0; i-- {
-			ig.p("\t\t%s()\n", ig.cleanupNames[i])
+		ig.wrapError(c, ig.errVar)
+		ig.failReturn(prevCleanup, injectSig)
+		ig.p("\t}\n")
+	}
+	if ig.cleanupsVar != "" && c.cleanup != NoCleanup {
+		ig.deferCleanup(len(ig.cleanupNames) - 1)
+	}
+	ig.postConstruct(lname, c, injectSig)
+}
+
+// postConstruct writes the call to the post-construct method of the value
+// lname provided by c, if it has one. If the method fails, the injector
+// cleans up the values provided so far, including lname, and returns the
+// error.
+func (ig *injectorGen) postConstruct(lname string, c *call, injectSig outputSignature) {
+	if c.postConstruct == "" {
+		return
+	}
+	ig.p("\tif %s := %s.%s(); %s != nil {\n", ig.errVar, lname, c.postConstruct, ig.errVar)
+	ig.wrapError(c, ig.errVar)
+	ig.failReturn(len(ig.cleanupNames), injectSig)
+	ig.p("\t}\n")
+}
+
+// recoverPanic writes a deferred function that recovers a panic from a
+// provider and calls the cleanup functions of the values built so far. The
+// panic is then returned as the injector's error if panicErr is true, or
+// resumed otherwise.
+func (ig *injectorGen) recoverPanic(name string, hasCleanups, panicErr bool) {
+	ig.recovering = true
+	if hasCleanups {
+		ig.cleanupsVar = disambiguate("cleanups", ig.nameInInjector)
+		ig.p("\tvar %s []func()\n", ig.cleanupsVar)
+	}
+	ig.p("\tdefer func() {\n")
+	ig.p("\t\tif r := recover(); r != nil {\n")
+	if hasCleanups {
+		ig.p("\t\t\tfor i := len(%s) - 1; i >= 0; i-- {\n", ig.cleanupsVar)
+		ig.p("\t\t\t\t%s[i]()\n", ig.cleanupsVar)
+		ig.p("\t\t\t}\n")
+	}
+	if panicErr {
+		ig.p("\t\t\t%s = %s(\"panic in %s: %%v\", r)\n", ig.errVar, ig.g.qualifiedID("fmt", "fmt", "Errorf"), name)
+	} else {
+		ig.p("\t\t\tpanic(r)\n")
+	}
+	ig.p("\t\t}\n")
+	ig.p("\t}()\n")
+}
+
+// deferCleanup writes a statement that adds the i'th cleanup function to the
+// cleanup functions called on a panic.
+func (ig *injectorGen) deferCleanup(i int) {
+	name := ig.cleanupNames[i]
+	switch ig.cleanupKinds[i] {
+	case CleanupFunc:
+		ig.p("\t%s = append(%s, %s)\n", ig.cleanupsVar, ig.cleanupsVar, name)
+	case CleanupFuncErr:
+		ig.p("\t%s = append(%s, func() { %s() })\n", ig.cleanupsVar, ig.cleanupsVar, name)
+	case CleanupFuncCtxErr:
+		ig.p("\t%s = append(%s, func() { %s(%s()) })\n", ig.cleanupsVar, ig.cleanupsVar, name, ig.g.qualifiedID("context", "context", "Background"))
+	}
+}
+
+// cleanupFuncType returns the type of a cleanup function of kind k. If ctx
+// is not empty, it is used as the name of the context parameter.
+func (ig *injectorGen) cleanupFuncType(k CleanupKind, ctx string) string {
+	switch k {
+	case CleanupFunc:
+		return "func()"
+	case CleanupFuncErr:
+		return "func() error"
+	case CleanupFuncCtxErr:
+		if ctx != "" {
+			ctx += " "
 		}
-		ig.p("\t\treturn %s", zeroValue(injectSig.out, ig.g.qualifyPkg))
-		if injectSig.cleanup {
-			ig.p(", nil")
+		return fmt.Sprintf("func(%s%s) error", ctx, ig.g.qualifiedID("context", "context", "Context"))
+	default:
+		panic("no cleanup")
+	}
+}
+
+// cleanupErrs reports whether any of the first n cleanup functions return
+// an error.
+func (ig *injectorGen) cleanupErrs(n int) bool {
+	for _, k := range ig.cleanupKinds[:n] {
+		if k != CleanupFunc {
+			return true
+		}
+	}
+	return false
+}
+
+// backgroundContext returns an expression for the context passed to the
+// first n cleanup functions when the caller doesn't provide one, or the
+// empty string if none of them take a context.
+func (ig *injectorGen) backgroundContext(n int) string {
+	for _, k := range ig.cleanupKinds[:n] {
+		if k == CleanupFuncCtxErr {
+			return ig.g.qualifiedID("context", "context", "Background") + "()"
 		}
-		// TODO(light): Give information about failing provider.
-		ig.p(", err\n")
-		ig.p("\t}\n")
 	}
+	return ""
+}
+
+// cleanupCalls writes calls to the first n cleanup functions in reverse
+// order. ctx is the context passed to func(context.Context) error cleanup
+// functions. The error from a cleanup function is assigned to errVar, and
+// onErr is run if it is not nil. If errVar is empty, errors are ignored.
+func (ig *injectorGen) cleanupCalls(n int, ctx, errVar, onErr string) {
+	for i := n - 1; i >= 0; i-- {
+		ig.cleanupCall(i, ctx, errVar, onErr)
+	}
+}
+
+// cleanupCall writes a call to the i'th cleanup function, like cleanupCalls.
+func (ig *injectorGen) cleanupCall(i int, ctx, errVar, onErr string) {
+	name, k := ig.cleanupNames[i], ig.cleanupKinds[i]
+	switch {
+	case k == CleanupFunc || k == CleanupFuncErr && errVar == "":
+		ig.p("\t\t%s()\n", name)
+		return
+	case k == CleanupFuncErr:
+		ig.p("\t\tif %s := %s(); %s != nil {\n", errVar, name, errVar)
+	case k == CleanupFuncCtxErr:
+		ig.p("\t\tif %s := %s(%s); %s != nil {\n", errVar, name, ctx, errVar)
+	}
+	ig.p("\t\t\t%s\n", onErr)
+	ig.p("\t\t}\n")
 }
 
 func (ig *injectorGen) structProviderCall(lname string, c *call) {
 	ig.p("\t%s", lname)
 	ig.p(" := ")
-	if _, ok := c.out.(*types.Pointer); ok {
+	out, _ := unqualify(c.out)
+	if _, ok := out.(*types.Pointer); ok {
 		ig.p("&")
 	}
-	ig.p("%s{\n", ig.g.qualifiedID(c.pkg.Name(), c.pkg.Path(), c.name))
+	ig.p("%s%s{\n", ig.g.qualifiedID(c.pkg.Name(), c.pkg.Path(), c.name), ig.typeArgList(c.typeArgs))
 	for i, a := range c.args {
 		ig.p("\t\t%s: ", c.fieldNames[i])
 		if a < len(ig.paramNames) {
@@ -747,6 +1927,77 @@ func (ig *injectorGen) fieldExpr(lname string, c *call) {
 	}
 }
 
+func (ig *injectorGen) interceptorProxy(lname string, c *call) {
+	ig.p("\t%s := &%s{\n", lname, ig.g.proxies[typeString(c.out)])
+	for i, field := range []string{"next", "interceptor"} {
+		ig.p("\t\t%s: ", field)
+		if a := c.args[i]; a < len(ig.paramNames) {
+			ig.p("%s", ig.paramNames[a])
+		} else {
+			ig.p("%s", ig.localNames[a-len(ig.paramNames)])
+		}
+		ig.p(",\n")
+	}
+	ig.p("\t}\n")
+}
+
+func (ig *injectorGen) multibindingLit(lname string, c *call) {
+	ig.p("\t%s := %s{\n", lname, types.TypeString(c.out, ig.g.qualifyPkg))
+	for i, a := range c.args {
+		ig.p("\t\t")
+		if c.keys != nil {
+			ig.writeAST(c.keyTypeInfos[i], c.keys[i])
+			ig.p(": ")
+		}
+		if a < len(ig.paramNames) {
+			ig.p("%s", ig.paramNames[a])
+		} else {
+			ig.p("%s", ig.localNames[a-len(ig.paramNames)])
+		}
+		ig.p(",\n")
+	}
+	ig.p("\t}\n")
+}
+
+// typeParamList returns the type parameter list for declaring a generic
+// injector, or the empty string if tparams is empty.
+func (ig *injectorGen) typeParamList(tparams *types.TypeParamList) string {
+	if tparams.Len() == 0 {
+		return ""
+	}
+	sb := new(strings.Builder)
+	sb.WriteString("[")
+	for i := 0; i < tparams.Len(); i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		tp := tparams.At(i)
+		sb.WriteString(tp.Obj().Name())
+		sb.WriteString(" ")
+		sb.WriteString(types.TypeString(tp.Constraint(), ig.g.qualifyPkg))
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+// typeArgList returns the type argument list for instantiating a generic
+// function or type, or the empty string if targs is empty.
+func (ig *injectorGen) typeArgList(targs []types.Type) string {
+	if len(targs) == 0 {
+		return ""
+	}
+	sb := new(strings.Builder)
+	sb.WriteString("[")
+	for i, t := range targs {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(types.TypeString(t, ig.g.qualifyPkg))
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
 // nameInInjector reports whether name collides with any other identifier
 // in the current injector.
 func (ig *injectorGen) nameInInjector(name string) bool {
@@ -768,6 +2019,14 @@ func (ig *injectorGen) nameInInjector(name string) bool {
 			return true
 		}
 	}
+	if name == ig.cleanupsVar {
+		return true
+	}
+	for _, l := range ig.otherNames {
+		if l == name {
+			return true
+		}
+	}
 	return ig.g.nameInFileScope(name)
 }
 
@@ -778,9 +2037,71 @@ func (ig *injectorGen) p(format string, args ...interface{}) {
 	ig.g.p(format, args...)
 }
 
+func (ig *injectorGen) writeAST(info *types.Info, node ast.Node) {
+	node = ig.g.rewritePkgRefs(info, node)
+	if ig.discard {
+		return
+	}
+	if err := printer.Fprint(&ig.g.buf, ig.g.pkg.Fset, node); err != nil {
+		panic(err)
+	}
+}
+
+// appType returns the type of the value held by out and the wireruntime.Hook
+// interface if out is *wireruntime.App[T]. Otherwise, it returns out and nil.
+func appType(out types.Type) (types.Type, *types.Interface) {
+	ptr, ok := out.(*types.Pointer)
+	if !ok {
+		return out, nil
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok {
+		return out, nil
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != "github.com/google/wire/wireruntime" || obj.Name() != "App" || named.TypeArgs().Len() != 1 {
+		return out, nil
+	}
+	hook, ok := obj.Pkg().Scope().Lookup("Hook").(*types.TypeName)
+	if !ok {
+		return out, nil
+	}
+	iface, ok := hook.Type().Underlying().(*types.Interface)
+	if !ok {
+		return out, nil
+	}
+	return named.TypeArgs().At(0), iface
+}
+
+// isObserverType reports whether t is wireruntime.Observer.
+func isObserverType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "github.com/google/wire/wireruntime" && obj.Name() == "Observer"
+}
+
+// hasContextParam reports whether the function with signature sig takes a
+// context.Context.
+func hasContextParam(sig *types.Signature) bool {
+	params := sig.Params()
+	for i := 0; i < params.Len(); i++ {
+		if isContextType(params.At(i).Type()) {
+			return true
+		}
+	}
+	return false
+}
+
 // zeroValue returns the shortest expression that evaluates to the zero
 // value for the given type.
 func zeroValue(t types.Type, qf types.Qualifier) string {
+	if _, ok := t.(*types.TypeParam); ok {
+		// A type parameter has no zero value literal.
+		return "*new(" + types.TypeString(t, qf) + ")"
+	}
 	switch u := t.Underlying().(type) {
 	case *types.Array, *types.Struct:
 		return types.TypeString(t, qf) + "{}"
@@ -820,11 +2141,24 @@ func typeVariableName(t types.Type, defaultName string, transform func(string) s
 		if t.Name() != "" {
 			names = append(names, t.Name())
 		}
+	case *types.TypeParam:
+		names = append(names, t.Obj().Name())
 	case *types.Named:
+		if _, name := unqualify(t); name != "" {
+			// Name a qualified type after its qualifier.
+			names = append(names, name)
+			break
+		}
 		obj := t.Obj()
 		if name := obj.Name(); name != "" {
 			names = append(names, name)
 		}
+		// Provide an alternate name prefixed with the type arguments' names if
+		// possible. E.g., in case of collisions, we'll use "userRepo" for
+		// Repo[User] instead of "repo2".
+		if name := typeArgsName(t.TypeArgs()); name != "" {
+			names = append(names, name+strings.Title(obj.Name()))
+		}
 		// Provide an alternate name prefixed with the package name if possible.
 		// E.g., in case of collisions, we'll use "fooCfg" instead of "cfg2".
 		if pkg := obj.Pkg(); pkg != nil && pkg.Name() != "" {
@@ -852,6 +2186,35 @@ func typeVariableName(t types.Type, defaultName string, transform func(string) s
 	return disambiguate(names[0], collides)
 }
 
+// typeArgsName joins the names of the given type arguments, or returns the
+// empty string if any of them does not have a name.
+func typeArgsName(targs *types.TypeList) string {
+	sb := new(strings.Builder)
+	for i := 0; i < targs.Len(); i++ {
+		t := targs.At(i)
+		if p, ok := t.(*types.Pointer); ok {
+			t = p.Elem()
+		}
+		var name string
+		switch t := t.(type) {
+		case *types.Basic:
+			name = t.Name()
+		case *types.Named:
+			name = t.Obj().Name()
+		case *types.TypeParam:
+			name = t.Obj().Name()
+		}
+		if name == "" {
+			return ""
+		}
+		if i > 0 {
+			name = strings.Title(name)
+		}
+		sb.WriteString(name)
+	}
+	return sb.String()
+}
+
 // unexport converts a name that is potentially exported to an unexported name.
 func unexport(name string) string {
 	if name == "" {
@@ -955,6 +2318,8 @@ func accessibleFrom(info *types.Info, node ast.Node, wantPkg string) error {
 }
 
 var (
-	errorType   = types.Universe.Lookup("error").Type()
-	cleanupType = types.NewSignature(nil, nil, nil, false)
+	errorType      = types.Universe.Lookup("error").Type()
+	cleanupType    = types.NewSignature(nil, nil, nil, false)
+	cleanupErrType = types.NewSignature(nil, nil, types.NewTuple(types.NewVar(token.NoPos, nil, "", errorType)), false)
+	closerType     = types.NewInterfaceType([]*types.Func{types.NewFunc(token.NoPos, nil, "Close", cleanupErrType)}, nil).Complete()
 )
@@ -24,6 +24,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -42,8 +43,8 @@ func TestWire(t *testing.T) {
 		t.Fatal(err)
 	}
 	// The marker function package source is needed to have the test cases
-	// type check. loadTestCase places this file at the well-known import path.
-	wireGo, err := ioutil.ReadFile(filepath.Join("..", "..", "wire.go"))
+	// type check. loadTestCase places these files at the well-known import path.
+	wireSrcs, err := loadWirePackage(filepath.Join("..", ".."))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -53,7 +54,7 @@ func TestWire(t *testing.T) {
 		if !ent.IsDir() || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
 			continue
 		}
-		test, err := loadTestCase(filepath.Join(testRoot, name), wireGo)
+		test, err := loadTestCase(filepath.Join(testRoot, name), wireSrcs)
 		if err != nil {
 			t.Error(err)
 			continue
@@ -194,6 +195,158 @@ func goBuildCheck(goToolPath, gopath string, test *testCase) error {
 	return nil
 }
 
+func TestGenerateVariants(t *testing.T) {
+	wireSrcs, err := loadWirePackage(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	test := &testCase{goFiles: wireSrcs}
+	test.goFiles["example.com/foo/foo.go"] = []byte(`package main
+
+import "fmt"
+
+type Greeting string
+
+func main() {
+	fmt.Println(injectGreeting())
+}
+`)
+	test.goFiles["example.com/foo/wire.go"] = []byte(`//go:build wireinject
+
+package main
+
+import "github.com/google/wire"
+
+func injectGreeting() Greeting {
+	panic(wire.Build(greetingSet))
+}
+`)
+	test.goFiles["example.com/foo/prod.go"] = []byte(`//go:build prod
+
+package main
+
+import "github.com/google/wire"
+
+var greetingSet = wire.NewSet(provideProdGreeting)
+
+func provideProdGreeting() Greeting { return "Hello" }
+`)
+	test.goFiles["example.com/foo/fake.go"] = []byte(`//go:build fake
+
+package main
+
+import "github.com/google/wire"
+
+var greetingSet = wire.NewSet(provideFakeGreeting)
+
+func provideFakeGreeting() Greeting { return "Fake" }
+`)
+	gopath, err := ioutil.TempDir("", "wire_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(gopath)
+	gopath, err = filepath.EvalSymlinks(gopath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := test.materialize(gopath); err != nil {
+		t.Fatal(err)
+	}
+	wd := filepath.Join(gopath, "src", "example.com")
+	variants := []Variant{{Name: "prod", Tags: "prod"}, {Name: "test", Tags: "fake"}}
+	gens, errs := Generate(context.Background(), wd, append(os.Environ(), "GOPATH="+gopath), []string{"example.com/foo"}, &GenerateOptions{Variants: variants})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if len(gens) != len(variants) {
+		t.Fatalf("got %d generated files, want %d", len(gens), len(variants))
+	}
+	tests := []struct {
+		file       string
+		constraint string
+		provider   string
+	}{
+		{"prod_wire_gen.go", "//go:build !wireinject && prod\n", "provideProdGreeting"},
+		{"test_wire_gen.go", "//go:build !wireinject && fake\n", "provideFakeGreeting"},
+	}
+	for i, test := range tests {
+		gen := gens[i]
+		if len(gen.Errs) > 0 {
+			t.Errorf("%s: %v", test.file, gen.Errs)
+			continue
+		}
+		if got := filepath.Base(gen.OutputPath); got != test.file {
+			t.Errorf("output path = %q; want file name %q", gen.OutputPath, test.file)
+		}
+		content := string(gen.Content)
+		if !strings.Contains(content, test.constraint) {
+			t.Errorf("%s does not contain %q:\n%s", test.file, test.constraint, content)
+		}
+		if !strings.Contains(content, `gen -variants "prod:prod,test:fake"`) {
+			t.Errorf("%s does not regenerate all variants:\n%s", test.file, content)
+		}
+		if !strings.Contains(content, test.provider) {
+			t.Errorf("%s does not call %s:\n%s", test.file, test.provider, content)
+		}
+	}
+
+	// A wire_gen.go left over from before the variants is reported.
+	stale := filepath.Join(gopath, "src", "example.com", "foo", "wire_gen.go")
+	if err := ioutil.WriteFile(stale, []byte("package main\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	gens, errs = Generate(context.Background(), wd, append(os.Environ(), "GOPATH="+gopath), []string{"example.com/foo"}, &GenerateOptions{Variants: variants})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	for _, gen := range gens {
+		if len(gen.Errs) == 0 || !strings.Contains(gen.Errs[0].Error(), "wire_gen.go conflicts with the variant files") {
+			t.Errorf("%s: errors = %v; want a conflict with wire_gen.go", filepath.Base(gen.OutputPath), gen.Errs)
+		}
+	}
+}
+
+func TestParseVariants(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    []Variant
+		wantErr bool
+	}{
+		{s: "", want: nil},
+		{s: "prod:prod", want: []Variant{{"prod", "prod"}}},
+		{s: "prod:prod,dev:dev,test:fake", want: []Variant{{"prod", "prod"}, {"dev", "dev"}, {"test", "fake"}}},
+		{s: " prod : prod  netgo ", want: []Variant{{"prod", "prod netgo"}}},
+		{s: "prod", wantErr: true},
+		{s: ":prod", wantErr: true},
+		{s: "prod:", wantErr: true},
+		{s: "prod:prod,prod:dev", wantErr: true},
+		{s: "a/b:prod", wantErr: true},
+		{s: "prod:!dev", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParseVariants(test.s)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("ParseVariants(%q) = %v, <nil>; want error", test.s, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseVariants(%q) error: %v", test.s, err)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseVariants(%q) (-want +got):\n%s", test.s, diff)
+		}
+		if len(got) > 0 {
+			if again, err := ParseVariants(FormatVariants(got)); err != nil || !cmp.Equal(again, got) {
+				t.Errorf("ParseVariants(FormatVariants(%v)) = %v, %v; want %v", got, again, err, got)
+			}
+		}
+	}
+}
+
 func TestUnexport(t *testing.T) {
 	tests := []struct {
 		name string
@@ -259,7 +412,14 @@ func TestTypeVariableName(t *testing.T) {
 		fooVarT         = types.NewNamed(types.NewTypeName(0, nil, "foo", stringT), stringT, nil)
 		nonameVarT      = types.NewNamed(types.NewTypeName(0, nil, "", stringT), stringT, nil)
 		barVarInFooPkgT = types.NewNamed(types.NewTypeName(0, types.NewPackage("my.example/foo", "foo"), "bar", stringT), stringT, nil)
+		boxT            = types.NewNamed(types.NewTypeName(0, nil, "box", nil), nil, nil)
 	)
+	boxT.SetTypeParams([]*types.TypeParam{types.NewTypeParam(types.NewTypeName(0, nil, "T", nil), types.NewInterfaceType(nil, nil))})
+	boxT.SetUnderlying(types.NewStruct(nil, nil))
+	boxOfFooT, err := types.Instantiate(nil, boxT, []types.Type{fooVarT}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
 	tests := []struct {
 		description     string
 		typ             types.Type
@@ -283,6 +443,11 @@ func TestTypeVariableName(t *testing.T) {
 		{"var in pkg type", barVarInFooPkgT, "", "", map[string]bool{}, "bar"},
 		{"var in pkg type with collision", barVarInFooPkgT, "", "", map[string]bool{"bar": true}, "fooBar"},
 		{"var in pkg type with double collision", barVarInFooPkgT, "", "", map[string]bool{"bar": true, "fooBar": true}, "bar2"},
+		{"instantiated type", boxOfFooT, "", "", map[string]bool{}, "box"},
+		{"instantiated type with collision", boxOfFooT, "", "", map[string]bool{"box": true}, "fooBox"},
+		{"instantiated type with double collision", boxOfFooT, "", "", map[string]bool{"box": true, "fooBox": true}, "box2"},
+		{"type parameter", boxT.TypeParams().At(0), "", "", map[string]bool{}, "T"},
+		{"type parameter with collision", boxT.TypeParams().At(0), "", "", map[string]bool{"T": true}, "T2"},
 	}
 	for _, test := range tests {
 		t.Run(fmt.Sprintf("%s: typeVariableName(%v, %q, %q, %v)", test.description, test.typ, test.defaultName, test.transformAppend, test.collides), func(t *testing.T) {
@@ -423,6 +588,31 @@ func scrubLineColumn(s string) (replacement string, n int) {
 	return ":x:y", n
 }
 
+// loadWirePackage reads the non-test Go files of the wire package in dir
+// and of its wireruntime package, keyed by their path under GOPATH/src.
+func loadWirePackage(dir string) (map[string][]byte, error) {
+	srcs := make(map[string][]byte)
+	for _, pkg := range []string{"", "wireruntime"} {
+		pkgDir := filepath.Join(dir, filepath.FromSlash(pkg))
+		ents, err := ioutil.ReadDir(pkgDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, ent := range ents {
+			name := ent.Name()
+			if ent.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+				continue
+			}
+			src, err := ioutil.ReadFile(filepath.Join(pkgDir, name))
+			if err != nil {
+				return nil, err
+			}
+			srcs[path.Join("github.com/google/wire", pkg, name)] = src
+		}
+	}
+	return srcs, nil
+}
+
 type testCase struct {
 	name                 string
 	pkg                  string
@@ -463,7 +653,7 @@ type testCase struct {
 //			program_out.txt
 //					expected output from the final compiled program,
 //					missing if wire_errs.txt is present
-func loadTestCase(root string, wireGoSrc []byte) (*testCase, error) {
+func loadTestCase(root string, wireSrcs map[string][]byte) (*testCase, error) {
 	name := filepath.Base(root)
 	pkg, err := ioutil.ReadFile(filepath.Join(root, "pkg"))
 	if err != nil {
@@ -492,8 +682,9 @@ func loadTestCase(root string, wireGoSrc []byte) (*testCase, error) {
 			return nil, fmt.Errorf("load test case %s: %v", name, err)
 		}
 	}
-	goFiles := map[string][]byte{
-		"github.com/google/wire/wire.go": wireGoSrc,
+	goFiles := make(map[string][]byte, len(wireSrcs))
+	for name, src := range wireSrcs {
+		goFiles[name] = src
 	}
 	err = filepath.Walk(root, func(src string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -549,12 +740,13 @@ func (test *testCase) materialize(gopath string) error {
 	const importPath = "example.com"
 	const depPath = "github.com/google/wire"
 	depLoc := filepath.Join(gopath, "src", filepath.FromSlash(depPath))
-	example := fmt.Sprintf("module %s\n\nrequire %s v0.1.0\nreplace %s => %s\n", importPath, depPath, depPath, depLoc)
+	const goVersion = "1.19"
+	example := fmt.Sprintf("module %s\n\ngo %s\n\nrequire %s v0.1.0\nreplace %s => %s\n", importPath, goVersion, depPath, depPath, depLoc)
 	gomod := filepath.Join(gopath, "src", filepath.FromSlash(importPath), "go.mod")
 	if err := ioutil.WriteFile(gomod, []byte(example), 0666); err != nil {
 		return fmt.Errorf("generate go.mod for %s: %v", gomod, err)
 	}
-	if err := ioutil.WriteFile(filepath.Join(depLoc, "go.mod"), []byte("module "+depPath+"\n"), 0666); err != nil {
+	if err := ioutil.WriteFile(filepath.Join(depLoc, "go.mod"), []byte("module "+depPath+"\n\ngo "+goVersion+"\n"), 0666); err != nil {
 		return fmt.Errorf("generate go.mod for %s: %v", depPath, err)
 	}
 	return nil
@@ -0,0 +1,29 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+// A Lazy is a value that is built the first time it is called. A provider
+// that takes a Lazy[T] argument receives a function that builds the T, and
+// the values it depends on that the injector doesn't build anyway, on the
+// first call, and returns the same T and error on every later call. If the
+// values have cleanup functions, they are called by the injector's cleanup
+// function if the Lazy was called. If such a Lazy is first called after the
+// injector's cleanup function, it cleans up the values right away and
+// returns wireruntime.ErrCleanedUp.
+//
+// Example:
+//
+//	func NewCLI(db wire.Lazy[*sql.DB]) *CLI { /* ... */ }
+type Lazy[T any] func() (T, error)
@@ -30,19 +30,24 @@ type ProviderSet struct{}
 
 // NewSet creates a new provider set that includes the providers in its
 // arguments. Each argument is a function value, a provider set, a call to
-// Struct, a call to Bind, a call to Value, a call to InterfaceValue or a call
-// to FieldsOf.
+// Struct, StructOf, Bind, BindTo, Value, ValueOf, InterfaceValue, FieldsOf,
+// FieldsFrom, Generic, Named, NamedParams, Into, IntoMap, Override, AutoClose,
+// PostConstruct, Decorate, Intercept, Factory, Optional, Default or Select.
 //
 // Passing a function value to NewSet declares that the function's first
 // return value type will be provided by calling the function. The arguments
 // to the function will come from the providers for their types. As such, all
 // the function's parameters must be of non-identical types. The function may
 // optionally return an error as its last return value and a cleanup function
-// as the second return value. A cleanup function must be of type func() and is
-// guaranteed to be called before the cleanup function of any of the
-// provider's inputs. If any provider returns an error, the injector function
-// will call all the appropriate cleanup functions and return the error from
-// the injector function.
+// as the second return value. A cleanup function must be of type func(),
+// func() error or func(context.Context) error and is guaranteed to be called
+// before the cleanup function of any of the provider's inputs. If any
+// provider returns an error, the injector function will call all the
+// appropriate cleanup functions and return the error from the injector
+// function, joined with any errors from the cleanup functions.
+//
+// The function value may also be an instantiation of a generic function,
+// like NewRepo[User], in which case only that instantiation is provided.
 //
 // Passing a ProviderSet to NewSet is the same as if the set's contents
 // were passed as arguments to NewSet directly.
@@ -67,7 +72,8 @@ func NewSet(...interface{}) ProviderSet {
 // to panic().
 //
 // The parameters of the injector function are used as inputs in the dependency
-// graph.
+// graph. The injector function may have type parameters, which may be used to
+// instantiate generic providers in the arguments to Build.
 //
 // Similar to provider functions passed into NewSet, the first return value is
 // the output of the injector function, the optional second return value is a
@@ -76,6 +82,9 @@ func NewSet(...interface{}) ProviderSet {
 // or cleanup functions, the corresponding return value must be present in the
 // injector function template.
 //
+// Build may also be passed injector options, like RecoverPanics, which change
+// how the injector function is generated. Options may not be passed to NewSet.
+//
 // Examples:
 //
 //	func injector(ctx context.Context) (*sql.DB, error) {
@@ -114,6 +123,19 @@ func Bind(iface, to interface{}) Binding {
 	return Binding{}
 }
 
+// BindTo declares that the concrete type T should be used to satisfy a
+// dependency on the interface type I. It is equivalent to
+// Bind(new(I), new(T)), but the types are given as type arguments.
+//
+// Example:
+//
+//	var MySet = wire.NewSet(
+//		wire.StructOf[MyFoo](),
+//		wire.BindTo[Fooer, MyFoo]())
+func BindTo[I, T any]() Binding {
+	return Binding{}
+}
+
 // bindToUsePointer is detected by the wire tool to indicate that Bind's second argument should take a pointer.
 // See https://github.com/google/wire/issues/120 for details.
 const bindToUsePointer = true
@@ -131,6 +153,19 @@ func Value(interface{}) ProvidedValue {
 	return ProvidedValue{}
 }
 
+// ValueOf binds an expression to provide the type T. Unlike Value, T may be
+// an interface type, in which case ValueOf is equivalent to InterfaceValue.
+// The same restrictions as Value apply to the expression.
+//
+// Example:
+//
+//	var MySet = wire.NewSet(
+//		wire.ValueOf[time.Duration](5*time.Second),
+//		wire.ValueOf[io.Reader](os.Stdin))
+func ValueOf[T any](v T) ProvidedValue {
+	return ProvidedValue{}
+}
+
 // InterfaceValue binds an expression to provide a specific interface type.
 // The first argument is a pointer to the interface which user wants to provide.
 // The second argument is the actual variable value whose type implements the
@@ -152,7 +187,9 @@ type StructProvider struct{}
 // The first argument must be a pointer to the struct type. For a struct type
 // Foo, Wire will use field-filling to provide both Foo and *Foo. The remaining
 // arguments are field names to fill in. As a special case, if a single name "*"
-// is given, then all of the fields in the struct will be filled in.
+// is given, then all of the fields in the struct will be filled in. A field
+// tagged with `wire:"name=foo"` is filled in with the value named "foo"; see
+// Named.
 //
 // For example:
 //
@@ -166,6 +203,17 @@ func Struct(structType interface{}, fieldNames ...string) StructProvider {
 	return StructProvider{}
 }
 
+// StructOf is like Struct, but the struct type is given as the type argument
+// T instead of as a pointer value.
+//
+// For example:
+//
+//	var Set = wire.NewSet(wire.StructOf[S]("MyFoo")) -> inject only S.MyFoo
+//	var Set = wire.NewSet(wire.StructOf[S]("*")) -> inject all fields
+func StructOf[T any](fieldNames ...string) StructProvider {
+	return StructProvider{}
+}
+
 // StructFields is a collection of the fields from a struct.
 type StructFields struct{}
 
@@ -194,3 +242,392 @@ type StructFields struct{}
 func FieldsOf(structType interface{}, fieldNames ...string) StructFields {
 	return StructFields{}
 }
+
+// FieldsFrom is like FieldsOf, but the struct type is given as the type
+// argument T, which must be a struct or a pointer to a struct.
+//
+// Example:
+//
+//	var Set = wire.NewSet(wire.FieldsFrom[*S]("MyFoo", "MyBar"))
+func FieldsFrom[T any](fieldNames ...string) StructFields {
+	return StructFields{}
+}
+
+// A GenericProvider is a generic provider function whose type arguments are
+// inferred by Wire.
+type GenericProvider struct{}
+
+// Generic declares that a generic provider function is instantiated as
+// needed to provide any type that matches its output type. Go requires
+// generic functions to be instantiated before they can be used as values, so
+// the argument must be an instantiation of the function, but its type
+// arguments are ignored. Instantiating the function with its constraints, if
+// they are ordinary interfaces, or with any, makes that clear. Every type
+// parameter of the function must appear in its first return value's type.
+//
+// Example:
+//
+//	type Entity interface { TableName() string }
+//
+//	func NewRepo[T Entity](db *sql.DB) *Repo[T] { /* ... */ }
+//
+//	// Set provides *Repo[T] for any T that satisfies Entity.
+//	var Set = wire.NewSet(wire.Generic(NewRepo[Entity]))
+func Generic(fn interface{}) GenericProvider {
+	return GenericProvider{}
+}
+
+// A NamedProvider is a provider whose output or inputs are qualified by
+// names.
+type NamedProvider struct{}
+
+// Named declares that the types provided by provider are qualified by name,
+// which must be a Go identifier. A qualified type is distinct from its base
+// type and from the same type qualified by other names, so Named allows an
+// injector to use several values of the same type. provider may be a
+// provider function, a call to Struct, StructOf or NamedParams, or a call to
+// Value, ValueOf or InterfaceValue.
+//
+// A provider requests a qualified type with NamedParams; a struct provider
+// requests one by tagging the field with `wire:"name=..."`.
+//
+// Example:
+//
+//	type Replicated struct {
+//		Primary *sql.DB `wire:"name=primary"`
+//		Replica *sql.DB `wire:"name=replica"`
+//	}
+//
+//	var Set = wire.NewSet(
+//		wire.Named("primary", NewPrimaryDB),
+//		wire.Named("replica", NewReplicaDB),
+//		wire.Struct(new(Replicated), "*"))
+func Named(name string, provider interface{}) NamedProvider {
+	return NamedProvider{}
+}
+
+// NamedParams declares a provider function whose parameters are qualified by
+// the given names, one per parameter. An empty name leaves the parameter
+// unqualified. See Named.
+//
+// Example:
+//
+//	func NewStore(primary, replica *sql.DB, log *Logger) *Store { /* ... */ }
+//
+//	var Set = wire.NewSet(wire.NamedParams(NewStore, "primary", "replica", ""))
+func NamedParams(fn interface{}, names ...string) NamedProvider {
+	return NamedProvider{}
+}
+
+// A Contribution adds an element to a slice or map type.
+type Contribution struct{}
+
+// Into declares that the value provided by provider is an element of the
+// slice type that slice points to. Unlike other providers, any number of
+// calls to Into may provide the same slice type, including calls in
+// different provider sets: the slice is made up of all the elements, in the
+// order that their provider sets are included. provider may be a provider
+// function, a call to Struct, StructOf, Named or NamedParams, or a call to
+// Value, ValueOf or InterfaceValue. Its output type must be assignable to
+// the slice's element type, but it is not otherwise provided.
+//
+// Example:
+//
+//	var Set = wire.NewSet(
+//		wire.Into(new([]Middleware), NewAuthMiddleware),
+//		wire.Into(new([]Middleware), NewLogMiddleware))
+func Into(slice interface{}, provider interface{}) Contribution {
+	return Contribution{}
+}
+
+// IntoMap is like Into, but adds an element with the given key to the map
+// type that m points to. key must be a constant, and two elements of the
+// same map type may not have the same key.
+//
+// Example:
+//
+//	var Set = wire.NewSet(
+//		wire.IntoMap(new(map[string]Plugin), "auth", NewAuthPlugin),
+//		wire.IntoMap(new(map[string]Plugin), "log", NewLogPlugin))
+func IntoMap(m interface{}, key interface{}, provider interface{}) Contribution {
+	return Contribution{}
+}
+
+// A ProviderOverride replaces the provider of a type in a provider set.
+type ProviderOverride struct{}
+
+// Override replaces the provider of each type that provider provides in the
+// enclosing set, including a provider from an imported set, which would
+// otherwise conflict with it. provider may be a provider function, a call to
+// Struct, StructOf, Named or NamedParams, a call to Value, ValueOf or
+// InterfaceValue, or a call to Bind or BindTo. It is an error if the set does
+// not otherwise provide any of provider's types.
+//
+// Override is intended for tests, where a few providers of a production set
+// are replaced with fakes.
+//
+// Example:
+//
+//	func initializeTestApp() *App {
+//		wire.Build(app.ProdSet, wire.Override(NewFakeClock))
+//		return nil
+//	}
+func Override(provider interface{}) ProviderOverride {
+	return ProviderOverride{}
+}
+
+// A DefaultProvider is a provider that yields to any other provider of the
+// same type.
+type DefaultProvider struct{}
+
+// Default declares that the provider function, struct provider, value or
+// interface binding passed to it is used only if nothing else in the
+// injector's provider set provides the same type. Unlike Override, which
+// replaces a provider where a set is used, Default is declared by the author
+// of a set to ship a provider that its users may replace without a conflict.
+// Several defaults for the same type conflict unless something else provides
+// the type.
+//
+// Example:
+//
+//	var LibrarySet = wire.NewSet(NewService, wire.Default(NewSystemClock))
+//
+//	func initializeService() *Service {
+//		wire.Build(LibrarySet, NewFakeClock)
+//		return nil
+//	}
+func Default(provider interface{}) DefaultProvider {
+	return DefaultProvider{}
+}
+
+// A Decorator is a provider function that wraps a provided value.
+type Decorator struct{}
+
+// Decorate declares that fn wraps the values of the type it provides after
+// they are provided and before any other provider receives them. fn must take
+// the value to wrap as its first argument; its other arguments are provided by
+// the enclosing set like those of any other provider, and it may return a
+// cleanup function and an error. Decorators of the same type are applied in
+// order, starting with the decorators from imported sets. The enclosing set
+// must include a provider for the type.
+//
+// Example:
+//
+//	func WithLogging(h Handler, l *Logger) Handler { /* ... */ }
+//
+//	var Set = wire.NewSet(NewHandler, NewLogger, wire.Decorate(WithLogging))
+func Decorate(fn interface{}) Decorator {
+	return Decorator{}
+}
+
+// An InterceptedInterface is an interface whose methods are intercepted.
+type InterceptedInterface struct{}
+
+// Intercept declares that the values of the interface type pointed to by
+// iface are wrapped in a generated proxy that calls a wireruntime.Interceptor
+// around every method call. The enclosing set must bind the interface to a
+// concrete type with Bind or BindTo and must provide the Interceptor.
+// Consumers of the interface receive the proxy instead of the concrete value.
+//
+// Example:
+//
+//	var Set = wire.NewSet(
+//		NewServer,
+//		wire.Bind(new(Handler), new(*Server)),
+//		NewMetricsInterceptor,
+//		wire.Intercept(new(Handler)),
+//	)
+func Intercept(iface interface{}) InterceptedInterface {
+	return InterceptedInterface{}
+}
+
+// A PostConstructMethod is a method called on provided values right after
+// they are built.
+type PostConstructMethod struct{}
+
+// PostConstruct declares that values of the type pointed to by typ are
+// validated or initialized right after they are provided, by calling their
+// Validate() error or Init() error method. The type must have exactly one of
+// these methods and be provided by a provider function or struct provider in
+// the enclosing set. If the method returns an error, the injector treats it
+// like an error returned by the provider, so the injector must return an
+// error.
+//
+// Example:
+//
+//	func (cfg *Config) Validate() error { /* ... */ }
+//
+//	var Set = wire.NewSet(
+//		wire.Struct(new(Config), "*"),
+//		wire.PostConstruct(new(*Config)),
+//	)
+func PostConstruct(typ interface{}) PostConstructMethod {
+	return PostConstructMethod{}
+}
+
+// A ClosingProvider is a provider whose cleanup function closes the value
+// that it provides.
+type ClosingProvider struct{}
+
+// AutoClose declares that the value provided by the provider function fn is
+// cleaned up by calling its Close() error method, as if fn returned
+// v.Close as a cleanup function. fn must not return a cleanup function of its
+// own. As with any other provider with a cleanup function of type
+// func() error, an injector that uses it must return a cleanup function that
+// returns an error, which includes the error from Close.
+//
+// Example:
+//
+//	func OpenDB(cfg *Config) (*sql.DB, error) { /* ... */ }
+//
+//	var Set = wire.NewSet(wire.AutoClose(OpenDB))
+func AutoClose(fn interface{}) ClosingProvider {
+	return ClosingProvider{}
+}
+
+// A FactoryProvider is a provider of a function that builds values from
+// arguments passed at call time.
+type FactoryProvider struct{}
+
+// Factory declares that the function type pointed to by fnType is provided
+// by calling the provider function fn. Each parameter of the function type
+// is passed to the argument of fn with the same type when the function is
+// called; the other arguments of fn are provided by the enclosing set once,
+// when the injector runs. The function type must return the type that fn
+// provides, along with the same kind of cleanup function as fn if it has
+// one, and an error if fn returns one.
+//
+// Example:
+//
+//	func NewSession(db *sql.DB, userID string, clock Clock) (*Session, error) { /* ... */ }
+//
+//	var Set = wire.NewSet(
+//		OpenDB,
+//		NewClock,
+//		wire.Factory(new(func(userID string) (*Session, error)), NewSession),
+//	)
+func Factory(fnType interface{}, fn interface{}) FactoryProvider {
+	return FactoryProvider{}
+}
+
+// An OptionalType is a type whose values may be left unprovided.
+type OptionalType struct{}
+
+// Optional declares that the type pointed to by typ may be left unprovided.
+// If an injector needs a value of the type and no provider, value or
+// injector argument provides it, the zero value of the type is used instead
+// of reporting an error. Providers that take an optional value must handle
+// the zero value, such as a nil interface.
+//
+// Example:
+//
+//	func NewServer(m Metrics) *Server { /* ... */ }
+//
+//	var Set = wire.NewSet(NewServer, wire.Optional(new(Metrics)))
+func Optional(typ interface{}) OptionalType {
+	return OptionalType{}
+}
+
+// A SelectProvider is a provider that chooses between provider sets when the
+// injector runs.
+type SelectProvider struct{}
+
+// Select declares that the type pointed to by typ is provided by one of the
+// provider sets in branches, chosen by the key that the provider function
+// keyFn returns when the injector runs. branches must be a map literal from
+// constant keys to provider sets. Each branch set must provide the type;
+// anything else that a branch needs and doesn't provide itself is provided by
+// the enclosing set, as are the arguments of keyFn. Only the chosen branch's
+// values are built. If the key doesn't match any branch, the injector returns
+// a *wireruntime.SelectError, so the injector must return an error.
+//
+// Example:
+//
+//	func StorageBackend(cfg *Config) string { return cfg.Backend }
+//
+//	var Set = wire.NewSet(
+//		LoadConfig,
+//		wire.Select(new(Storage), StorageBackend, map[string]wire.ProviderSet{
+//			"s3":  wire.NewSet(NewS3Storage, wire.Bind(new(Storage), new(*S3Storage))),
+//			"gcs": wire.NewSet(NewGCSStorage, wire.Bind(new(Storage), new(*GCSStorage))),
+//		}),
+//	)
+func Select(typ interface{}, keyFn interface{}, branches interface{}) SelectProvider {
+	return SelectProvider{}
+}
+
+// An InjectorOption changes how an injector function is generated. Injector
+// options may only be passed to Build.
+type InjectorOption struct{}
+
+// RecoverPanics is an injector option that makes the injector function
+// recover a panic from a provider, call the cleanup functions of the values
+// provided so far in reverse order, and then panic again with the same value.
+// The wire gen command's -recover_panics flag sets it for every injector.
+//
+// Example:
+//
+//	func initializeServer() (*Server, func(), error) {
+//		wire.Build(ServerSet, wire.RecoverPanics())
+//		return nil, nil, nil
+//	}
+func RecoverPanics() InjectorOption {
+	return InjectorOption{}
+}
+
+// PanicsAsErrors is an injector option like RecoverPanics, except that after
+// the cleanup functions are called, the injector function returns an error
+// describing the panic instead of panicking again. The injector function must
+// return an error. The wire gen command's -panics_as_errors flag sets it for
+// every injector that returns an error.
+func PanicsAsErrors() InjectorOption {
+	return InjectorOption{}
+}
+
+// Parallel is an injector option that makes the injector function call
+// provider functions that don't depend on each other concurrently, in separate
+// goroutines. If any of them return an error, the injector function waits for
+// the others, calls the cleanup functions of the values provided so far and
+// returns the errors. With RecoverPanics or PanicsAsErrors, a panic in one of
+// those goroutines is recovered once the others have finished, as if the
+// injector function had panicked itself.
+//
+// Example:
+//
+//	func initializeServer(cfg *Config) (*Server, func(), error) {
+//		wire.Build(ServerSet, wire.Parallel())
+//		return nil, nil, nil
+//	}
+func Parallel() InjectorOption {
+	return InjectorOption{}
+}
+
+// CheckContext is an injector option that makes the injector function check
+// whether its context.Context argument is done between provider function
+// calls. If it is, the injector function calls the cleanup functions of the
+// values provided so far and returns the context's error. The injector
+// function must take a context.Context and return an error.
+//
+// Example:
+//
+//	func initializeServer(ctx context.Context) (*Server, func(), error) {
+//		wire.Build(ServerSet, wire.CheckContext())
+//		return nil, nil, nil
+//	}
+func CheckContext() InjectorOption {
+	return InjectorOption{}
+}
+
+// WrapErrors is an injector option that makes the injector function wrap the
+// errors returned by provider functions in a *wireruntime.ProviderError, which
+// names the provider function and the type it provides.
+//
+// Example:
+//
+//	func initializeServer(cfg *Config) (*Server, func(), error) {
+//		wire.Build(ServerSet, wire.WrapErrors())
+//		return nil, nil, nil
+//	}
+func WrapErrors() InjectorOption {
+	return InjectorOption{}
+}
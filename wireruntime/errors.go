@@ -0,0 +1,96 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireruntime
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A ProviderError is an error returned by a provider function, annotated
+// with the provider that returned it. Injector functions built with the
+// wire.WrapErrors option return a *ProviderError when a provider function
+// fails. Use errors.Is and errors.As to inspect the provider's error.
+type ProviderError struct {
+	// Provider is the provider function's package path and name, like
+	// "example.com/db.Open".
+	Provider string
+	// Type is the type that the provider function provides, like
+	// "*database/sql.DB".
+	Type string
+	// Err is the error that the provider function returned.
+	Err error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("wire: %s (%s): %v", e.Provider, e.Type, e.Err)
+}
+
+// Unwrap returns the error that the provider function returned.
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// A SelectError is returned by an injector function when the key of a
+// wire.Select doesn't match any of its branches.
+type SelectError struct {
+	// Type is the type that the Select provides, like "example.com/app.Storage".
+	Type string
+	// Key is the key that didn't match.
+	Key interface{}
+}
+
+func (e *SelectError) Error() string {
+	return fmt.Sprintf("wire: no branch of %s selected by key %v", e.Type, e.Key)
+}
+
+// JoinErrors returns an error that wraps the non-nil errors in errs, or nil
+// if there are none. If there is only one, it is returned as is. It is like
+// errors.Join, which isn't available in every Go version that this package
+// supports. Injector functions use it to report the errors of several cleanup
+// functions.
+func JoinErrors(errs ...error) error {
+	var e joinError
+	for _, err := range errs {
+		if err != nil {
+			e = append(e, err)
+		}
+	}
+	switch len(e) {
+	case 0:
+		return nil
+	case 1:
+		return e[0]
+	default:
+		return e
+	}
+}
+
+// A joinError is an error that wraps several errors.
+type joinError []error
+
+func (e joinError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the wrapped errors, which errors.Is and errors.As check
+// since Go 1.20.
+func (e joinError) Unwrap() []error {
+	return e
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireruntime
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJoinErrors(t *testing.T) {
+	errA := errors.New("a")
+	errB := fmt.Errorf("b: %w", errA)
+	tests := []struct {
+		name    string
+		errs    []error
+		want    []error
+		wantMsg string
+	}{
+		{name: "None"},
+		{name: "AllNil", errs: []error{nil, nil}},
+		{name: "One", errs: []error{nil, errA, nil}, want: []error{errA}, wantMsg: "a"},
+		{name: "Several", errs: []error{errA, nil, errB}, want: []error{errA, errB}, wantMsg: "a\nb: a"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := JoinErrors(test.errs...)
+			if len(test.want) == 0 {
+				if err != nil {
+					t.Fatalf("JoinErrors(%v) = %v; want <nil>", test.errs, err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("JoinErrors(%v) = <nil>; want error", test.errs)
+			}
+			if got := err.Error(); got != test.wantMsg {
+				t.Errorf("JoinErrors(%v).Error() = %q; want %q", test.errs, got, test.wantMsg)
+			}
+			if len(test.want) == 1 && err != test.want[0] {
+				t.Errorf("JoinErrors(%v) = %#v; want the error itself", test.errs, err)
+			}
+			if diff := cmp.Diff(test.want, unwrapAll(err), cmp.Comparer(sameError)); diff != "" {
+				t.Errorf("JoinErrors(%v) wraps (-want +got):\n%s", test.errs, diff)
+			}
+		})
+	}
+}
+
+// unwrapAll returns the errors that err joins, or err itself if it doesn't
+// join several errors.
+func unwrapAll(err error) []error {
+	if u, ok := err.(interface{ Unwrap() []error }); ok {
+		return u.Unwrap()
+	}
+	return []error{err}
+}
+
+func sameError(a, b error) bool {
+	return a == b
+}
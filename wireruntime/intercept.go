@@ -0,0 +1,40 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireruntime
+
+// An Interceptor is called around every method call on an interface value
+// intercepted with wire.Intercept.
+type Interceptor interface {
+	// Intercept is called for each method call. It calls invoke to call
+	// the method on the underlying value, which sets call.Results. It may
+	// instead set call.Results itself, for example to inject a fault.
+	Intercept(call *MethodCall, invoke func())
+}
+
+// A MethodCall describes a call to a method of an intercepted interface.
+type MethodCall struct {
+	// Interface is the interface's package path and name, like
+	// "example.com/server.Handler".
+	Interface string
+	// Method is the name of the called method.
+	Method string
+	// Args are the arguments of the call. A variadic argument is passed as
+	// a slice.
+	Args []interface{}
+	// Results are the results of the call once invoke returns. The caller
+	// receives the Results that are set when Intercept returns; a missing or
+	// mistyped result is received as its type's zero value.
+	Results []interface{}
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireruntime
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/google/wire"
+)
+
+// ErrCleanedUp is returned by a wire.Lazy whose values have cleanup
+// functions when it is first called after the cleanup function of its
+// injector. The values it built are cleaned up right away.
+var ErrCleanedUp = errors.New("wire: lazy value built after its injector was cleaned up")
+
+// NewLazy returns a wire.Lazy that calls fn the first time it is called.
+// It is called by generated injector functions.
+func NewLazy[T any](fn func() (T, error)) wire.Lazy[T] {
+	var (
+		once sync.Once
+		v    T
+		err  error
+	)
+	return func() (T, error) {
+		once.Do(func() {
+			v, err = fn()
+		})
+		return v, err
+	}
+}
+
+// A LazyCleanup holds the cleanup function of type F of the values built
+// by a wire.Lazy until the injector's cleanup function takes it. It is used
+// by generated injector functions.
+type LazyCleanup[F any] struct {
+	mu    sync.Mutex
+	fn    F
+	taken bool
+}
+
+// NewLazyCleanup returns a LazyCleanup that holds noop, a cleanup function
+// that does nothing.
+func NewLazyCleanup[F any](noop F) *LazyCleanup[F] {
+	return &LazyCleanup[F]{fn: noop}
+}
+
+// Set replaces the held cleanup function with fn and reports whether it
+// did. Set fails once Take has been called; the caller must then call fn
+// itself.
+func (c *LazyCleanup[F]) Set(fn F) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.taken {
+		return false
+	}
+	c.fn = fn
+	return true
+}
+
+// Take returns the held cleanup function. Later calls to Set fail.
+func (c *LazyCleanup[F]) Take() F {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.taken = true
+	return c.fn
+}
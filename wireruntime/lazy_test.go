@@ -0,0 +1,123 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireruntime
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/wire"
+)
+
+func TestNewLazy(t *testing.T) {
+	errBuild := errors.New("build failed")
+	calls := 0
+	lazy := NewLazy(func() (int, error) {
+		calls++
+		return 42, errBuild
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := lazy(); v != 42 || err != errBuild {
+				t.Errorf("lazy() = %d, %v; want 42, %v", v, err, errBuild)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("fn called %d times; want 1", calls)
+	}
+}
+
+func TestLazyCleanup(t *testing.T) {
+	var log []string
+	record := func(s string) func() {
+		return func() { log = append(log, s) }
+	}
+	c := NewLazyCleanup(record("noop"))
+	if !c.Set(record("first")) {
+		t.Fatal("Set before Take = false; want true")
+	}
+	if !c.Set(record("second")) {
+		t.Fatal("second Set before Take = false; want true")
+	}
+	c.Take()()
+	if c.Set(record("late")) {
+		t.Error("Set after Take = true; want false")
+	}
+	// The late cleanup function didn't replace the taken one.
+	c.Take()()
+	if diff := cmp.Diff([]string{"second", "second"}, log); diff != "" {
+		t.Errorf("cleanup calls (-want +got):\n%s", diff)
+	}
+}
+
+func TestLazyCleanupNotSet(t *testing.T) {
+	called := false
+	c := NewLazyCleanup(func() { called = true })
+	c.Take()()
+	if !called {
+		t.Error("Take did not return the noop cleanup function")
+	}
+}
+
+// TestLazyCleanedUp tests a wire.Lazy built like the ones in generated
+// injector functions, whose value has a cleanup function.
+func TestLazyCleanedUp(t *testing.T) {
+	tests := []struct {
+		name         string
+		cleanupFirst bool
+		wantErr      error
+	}{
+		{name: "BeforeCleanup", cleanupFirst: false, wantErr: nil},
+		{name: "AfterCleanup", cleanupFirst: true, wantErr: ErrCleanedUp},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			closed := 0
+			holder := NewLazyCleanup(func() {})
+			var lazy wire.Lazy[string] = NewLazy(func() (string, error) {
+				v, cleanup := "value", func() { closed++ }
+				if !holder.Set(cleanup) {
+					cleanup()
+					return "", ErrCleanedUp
+				}
+				return v, nil
+			})
+			injectorCleanup := func() { holder.Take()() }
+			if test.cleanupFirst {
+				injectorCleanup()
+			}
+			_, err := lazy()
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("lazy() error = %v; want %v", err, test.wantErr)
+			}
+			if !test.cleanupFirst {
+				if closed != 0 {
+					t.Fatalf("value closed %d times before the injector's cleanup; want 0", closed)
+				}
+				injectorCleanup()
+			}
+			if closed != 1 {
+				t.Errorf("value closed %d times; want 1", closed)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireruntime
+
+import (
+	"context"
+)
+
+// A Hook is a value that must be started before use and stopped when it is
+// no longer needed, like a server or a background worker.
+type Hook interface {
+	// Start starts the value. It should return once the value is ready,
+	// leaving any long-running work in other goroutines.
+	Start(ctx context.Context) error
+	// Stop stops the value.
+	Stop(ctx context.Context) error
+}
+
+// An App holds the value built by an injector function together with the
+// provided values that implement Hook. An injector function that returns an
+// *App[T] builds a T as if it returned T and adds every provided value that
+// implements Hook to the App, in the order they were provided. Values passed
+// as injector arguments are not added.
+//
+// Example:
+//
+//	func initializeServer(cfg *Config) (*wireruntime.App[*Server], func(), error) {
+//		wire.Build(ServerSet)
+//		return nil, nil, nil
+//	}
+type App[T any] struct {
+	// Value is the value built by the injector function.
+	Value T
+
+	hooks   []Hook
+	started int
+}
+
+// NewApp returns an App that holds v and starts the given hooks in order.
+// It is called by generated injector functions.
+func NewApp[T any](v T, hooks ...Hook) *App[T] {
+	return &App[T]{Value: v, hooks: hooks}
+}
+
+// Start starts the hooks in the order they were provided, so that each value
+// is started after the values it depends on. If a hook fails to start, Start
+// stops the hooks that were already started in reverse order and returns the
+// errors.
+func (a *App[T]) Start(ctx context.Context) error {
+	for a.started < len(a.hooks) {
+		if err := a.hooks[a.started].Start(ctx); err != nil {
+			return JoinErrors(err, a.Stop(ctx))
+		}
+		a.started++
+	}
+	return nil
+}
+
+// Stop stops the started hooks in the reverse order that they were started
+// and returns their errors. Stop calls every hook's Stop method even if
+// some of them fail.
+func (a *App[T]) Stop(ctx context.Context) error {
+	var errs []error
+	for ; a.started > 0; a.started-- {
+		if err := a.hooks[a.started-1].Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return JoinErrors(errs...)
+}
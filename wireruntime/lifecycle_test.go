@@ -0,0 +1,115 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireruntime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testHook is a Hook that records its calls in log.
+type testHook struct {
+	name     string
+	log      *[]string
+	startErr error
+	stopErr  error
+}
+
+func (h *testHook) Start(context.Context) error {
+	*h.log = append(*h.log, "start "+h.name)
+	return h.startErr
+}
+
+func (h *testHook) Stop(context.Context) error {
+	*h.log = append(*h.log, "stop "+h.name)
+	return h.stopErr
+}
+
+func TestAppStartStop(t *testing.T) {
+	var log []string
+	app := NewApp("v", &testHook{name: "a", log: &log}, &testHook{name: "b", log: &log}, &testHook{name: "c", log: &log})
+	ctx := context.Background()
+	if err := app.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := app.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	// A second Stop has nothing left to stop.
+	if err := app.Stop(ctx); err != nil {
+		t.Fatalf("second Stop: %v", err)
+	}
+	want := []string{"start a", "start b", "start c", "stop c", "stop b", "stop a"}
+	if diff := cmp.Diff(want, log); diff != "" {
+		t.Errorf("hook calls (-want +got):\n%s", diff)
+	}
+	if app.Value != "v" {
+		t.Errorf("Value = %q; want %q", app.Value, "v")
+	}
+}
+
+func TestAppStartFailure(t *testing.T) {
+	var log []string
+	errStartB := errors.New("start b failed")
+	errStopA := errors.New("stop a failed")
+	app := NewApp(0,
+		&testHook{name: "a", log: &log, stopErr: errStopA},
+		&testHook{name: "b", log: &log, startErr: errStartB},
+		&testHook{name: "c", log: &log})
+	ctx := context.Background()
+	err := app.Start(ctx)
+	if err == nil {
+		t.Fatal("Start succeeded; want error")
+	}
+	if got, want := err.Error(), "start b failed\nstop a failed"; got != want {
+		t.Errorf("Start error = %q; want %q", got, want)
+	}
+	if diff := cmp.Diff([]error{errStartB, errStopA}, unwrapAll(err), cmp.Comparer(sameError)); diff != "" {
+		t.Errorf("Start error wraps (-want +got):\n%s", diff)
+	}
+	// The started hooks were rolled back, so there is nothing to stop.
+	if err := app.Stop(ctx); err != nil {
+		t.Errorf("Stop after failed Start: %v", err)
+	}
+	want := []string{"start a", "start b", "stop a"}
+	if diff := cmp.Diff(want, log); diff != "" {
+		t.Errorf("hook calls (-want +got):\n%s", diff)
+	}
+}
+
+func TestAppStopErrors(t *testing.T) {
+	var log []string
+	errStopA := errors.New("stop a failed")
+	errStopC := errors.New("stop c failed")
+	app := NewApp(0,
+		&testHook{name: "a", log: &log, stopErr: errStopA},
+		&testHook{name: "b", log: &log},
+		&testHook{name: "c", log: &log, stopErr: errStopC})
+	ctx := context.Background()
+	if err := app.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	err := app.Stop(ctx)
+	if diff := cmp.Diff([]error{errStopC, errStopA}, unwrapAll(err), cmp.Comparer(sameError)); diff != "" {
+		t.Errorf("Stop error wraps (-want +got):\n%s", diff)
+	}
+	want := []string{"start a", "start b", "start c", "stop c", "stop b", "stop a"}
+	if diff := cmp.Diff(want, log); diff != "" {
+		t.Errorf("hook calls (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 The Wire Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireruntime
+
+import (
+	"time"
+)
+
+// An Observer is notified when an injector function calls a provider
+// function. An injector function that takes an Observer argument calls its
+// methods before and after each provider function call. Struct providers,
+// values and interface bindings are not observed. With the Parallel option,
+// the methods may be called concurrently.
+//
+// Example:
+//
+//	func initializeServer(ctx context.Context, o wireruntime.Observer) (*Server, error) {
+//		wire.Build(ServerSet)
+//		return nil, nil
+//	}
+type Observer interface {
+	// ProviderStarted is called before the provider function is called.
+	ProviderStarted(info ProviderInfo)
+	// ProviderFinished is called after the provider function returns
+	// with the time that the call took and the error that it returned,
+	// if any.
+	ProviderFinished(info ProviderInfo, d time.Duration, err error)
+}
+
+// ProviderInfo describes a provider function called by an injector function.
+type ProviderInfo struct {
+	// Name is the provider function's package path and name, like
+	// "example.com/db.Open".
+	Name string
+	// Type is the type that the provider function provides, like
+	// "*database/sql.DB".
+	Type string
+}
+
+// Observe calls o.ProviderStarted for the provider function with the given
+// name and provided type, and returns a function that calls
+// o.ProviderFinished with the time since Observe was called. It is called by
+// generated injector functions. If o is nil, Observe does nothing.
+func Observe(o Observer, name, typ string) func(err error) {
+	if o == nil {
+		return func(error) {}
+	}
+	info := ProviderInfo{Name: name, Type: typ}
+	o.ProviderStarted(info)
+	start := time.Now()
+	return func(err error) {
+		o.ProviderFinished(info, time.Since(start), err)
+	}
+}